@@ -11,126 +11,193 @@
 package choose
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/paginator"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mattn/go-runewidth"
+
+	"github.com/charmbracelet/gum/internal/preview"
+	"github.com/charmbracelet/gum/internal/timeout"
 )
 
+// columnGap is the number of spaces between the columns of a grid.
+const columnGap = 2
+
+// numberKeys are the keys that select the items on the current page when the
+// items are numbered.
+var numberKeys = []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "0"}
+
 type model struct {
+	header           string
 	height           int
+	columns          int
+	itemLines        int
+	cycle            bool
+	numbered         bool
 	cursor           string
 	selectedPrefix   string
 	unselectedPrefix string
 	cursorPrefix     string
 	items            []item
+	display          []int
+	visible          []int
+	filter           textinput.Model
+	filtering        bool
+	preview          preview.Model
 	quitting         bool
 	index            int
 	limit            int
 	numSelected      int
+	numSelections    int
 	paginator        paginator.Model
 	aborted          bool
+	hasTimeout       bool
+	timeout          time.Duration
+	defaultIndex     int
 
 	// styles
 	cursorStyle       lipgloss.Style
 	itemStyle         lipgloss.Style
 	selectedItemStyle lipgloss.Style
+	groupStyle        lipgloss.Style
+	descriptionStyle  lipgloss.Style
+	disabledStyle     lipgloss.Style
+	numberStyle       lipgloss.Style
+	timeoutStyle      lipgloss.Style
+	headerStyle       lipgloss.Style
 }
 
 type item struct {
-	text     string
-	selected bool
+	text        string
+	value       string
+	description string
+	selected    bool
+
+	// order is the position of the item in the sequence of selections made,
+	// which is used to print the items in the order they were selected.
+	order int
+
+	// group is set for section headers, which are displayed between options
+	// but can never be selected or hold the cursor.
+	group bool
+
+	// disabled items are displayed and can hold the cursor, but can never be
+	// selected.
+	disabled bool
 }
 
-func (m model) Init() tea.Cmd { return nil }
+// output returns the string that is printed when the item is chosen.
+func (i item) output() string {
+	if i.value != "" {
+		return i.value
+	}
+	return i.text
+}
+
+func (m model) Init() tea.Cmd {
+	if m.timeout > 0 {
+		return tea.Batch(timeout.Tick(m.timeout), m.preview.Init())
+	}
+	return m.preview.Init()
+}
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if !m.preview.Enabled() {
+		return m.update(msg)
+	}
+
+	var previewCmd tea.Cmd
+	m.preview, previewCmd = m.preview.Update(msg)
+	tm, cmd := m.update(msg)
+	m = tm.(model)
+
+	// The cursor may have moved, so the preview has to follow it.
+	highlightCmd := m.preview.SetItem(m.highlighted())
+	return m, tea.Batch(cmd, previewCmd, highlightCmd)
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		return m, nil
 
+	case timeout.TickMsg:
+		m.timeout -= time.Duration(msg)
+		if m.timeout <= 0 {
+			// Nobody made a choice in time, so pick the default option if
+			// there is one and nothing has been selected yet.
+			if m.defaultIndex >= 0 && m.numSelected < 1 {
+				m.setSelected(m.defaultIndex, true)
+			}
+			if m.numSelected < 1 && len(m.visible) > 0 && !m.items[m.visible[m.index]].disabled {
+				m.setSelected(m.visible[m.index], true)
+			}
+			m.quitting = true
+			return m, tea.Quit
+		}
+		return m, timeout.Tick(m.timeout)
+
 	case tea.KeyMsg:
-		start, end := m.paginator.GetSliceBounds(len(m.items))
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+
 		switch keypress := msg.String(); keypress {
 		case "down", "j", "ctrl+n":
-			m.index++
-			if m.index >= len(m.items) {
-				m.index = 0
-				m.paginator.Page = 0
-			}
-			if m.index >= end {
-				m.paginator.NextPage()
-			}
+			m.cursorDown()
 		case "up", "k", "ctrl+p":
-			m.index--
-			if m.index < 0 {
-				m.index = len(m.items) - 1
-				m.paginator.Page = m.paginator.TotalPages - 1
-			}
-			if m.index < start {
-				m.paginator.PrevPage()
-			}
+			m.cursorUp()
 		case "right", "l", "ctrl+f":
-			m.index = clamp(m.index+m.height, 0, len(m.items)-1)
-			m.paginator.NextPage()
-		case "left", "h", "ctrl+b":
-			m.index = clamp(m.index-m.height, 0, len(m.items)-1)
-			m.paginator.PrevPage()
-		case "G":
-			m.index = len(m.items) - 1
-			m.paginator.Page = m.paginator.TotalPages - 1
-		case "g":
-			m.index = 0
-			m.paginator.Page = 0
-		case "a":
-			if m.limit <= 1 {
+			if m.columns > 1 && keypress != "ctrl+f" {
+				m.cursorNext()
 				break
 			}
-			for i := range m.items {
-				if m.numSelected >= m.limit {
-					break // do not exceed given limit
-				}
-				if m.items[i].selected {
-					continue
-				}
-				m.items[i].selected = true
-				m.numSelected++
-			}
-		case "A":
-			if m.limit <= 1 {
+			m.index = clamp(m.index+m.perPage(), 0, len(m.visible)-1)
+			m.skipGroups(1)
+		case "left", "h", "ctrl+b":
+			if m.columns > 1 && keypress != "ctrl+b" {
+				m.cursorPrev()
 				break
 			}
-			for i := range m.items {
-				m.items[i].selected = false
-			}
-			m.numSelected = 0
+			m.index = clamp(m.index-m.perPage(), 0, len(m.visible)-1)
+			m.skipGroups(-1)
+		case "G":
+			m.index = len(m.visible) - 1
+			m.skipGroups(-1)
+		case "g":
+			m.index = 0
+			m.skipGroups(1)
+		case "a", "ctrl+a":
+			m.selectAll()
+		case "A", "ctrl+d":
+			m.deselectAll()
+		case "/":
+			m.filtering = true
+			return m, m.filter.Focus()
 		case "ctrl+c", "esc":
 			m.aborted = true
 			m.quitting = true
 			return m, tea.Quit
 		case " ", "x":
-			if m.limit == 1 {
-				break // no op
-			}
-
-			if m.items[m.index].selected {
-				m.items[m.index].selected = false
-				m.numSelected--
-			} else if m.numSelected < m.limit {
-				m.items[m.index].selected = true
-				m.numSelected++
-			}
+			m.toggleSelection()
 		case "enter":
-			m.quitting = true
-			// If the user hasn't selected any items in a multi-select.
-			// Then we select the item that they have pressed enter on. If they
-			// have selected items, then we simply return them.
-			if m.numSelected < 1 {
-				m.items[m.index].selected = true
+			return m.choose()
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9", "0":
+			if !m.numbered {
+				break
+			}
+			if i, ok := m.numberedItem(keypress); ok {
+				m.index = i
+				if m.limit == 1 {
+					return m.choose()
+				}
+				m.toggleSelection()
 			}
-			return m, tea.Quit
 		}
 	}
 
@@ -139,43 +206,417 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// updateFilter handles key presses while the user is typing a filter query.
+// Navigation and selection keys that cannot be part of a query keep working
+// so that the user can narrow the list and pick without leaving the input.
+func (m model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.aborted = true
+		m.quitting = true
+		return m, tea.Quit
+	case "esc":
+		m.filtering = false
+		m.filter.Blur()
+		m.filter.SetValue("")
+		m.updateVisible()
+		return m, nil
+	case "down", "ctrl+n":
+		m.cursorDown()
+		return m, nil
+	case "up", "ctrl+p":
+		m.cursorUp()
+		return m, nil
+	case "tab":
+		m.toggleSelection()
+		return m, nil
+	case "ctrl+a":
+		if m.limit > 1 {
+			m.selectAll()
+			return m, nil
+		}
+	case "ctrl+d":
+		if m.limit > 1 {
+			m.deselectAll()
+			return m, nil
+		}
+	case "enter":
+		return m.choose()
+	}
+
+	var cmd tea.Cmd
+	query := m.filter.Value()
+	m.filter, cmd = m.filter.Update(msg)
+	if m.filter.Value() != query {
+		m.updateVisible()
+	}
+	return m, cmd
+}
+
+// choose finishes the selection. If the user hasn't selected any items in a
+// multi-select, then we select the item that they have pressed enter on. If
+// they have selected items, then we simply return them.
+func (m model) choose() (tea.Model, tea.Cmd) {
+	if m.numSelected < 1 {
+		if len(m.visible) == 0 || m.items[m.visible[m.index]].disabled {
+			return m, nil
+		}
+		m.setSelected(m.visible[m.index], true)
+	}
+	m.quitting = true
+	return m, tea.Quit
+}
+
+// cursorDown moves the cursor down one row, which is the next item unless the
+// items are laid out in a grid.
+func (m *model) cursorDown() {
+	if m.index+m.columns >= len(m.visible) && !m.cycle {
+		return
+	}
+	m.index += m.columns
+	if m.index >= len(m.visible) {
+		// Wrap around to the top of the same column.
+		m.index %= m.columns
+	}
+	m.skipGroups(1)
+}
+
+// cursorUp moves the cursor up one row, which is the previous item unless the
+// items are laid out in a grid.
+func (m *model) cursorUp() {
+	if m.index-m.columns < 0 && !m.cycle {
+		return
+	}
+	column := m.index % m.columns
+	m.index -= m.columns
+	if m.index < 0 {
+		// Wrap around to the bottom of the same column.
+		last := len(m.visible) - 1
+		m.index = last - last%m.columns + column
+		if m.index > last {
+			m.index -= m.columns
+		}
+	}
+	m.skipGroups(-1)
+}
+
+// cursorNext moves the cursor to the next item, in reading order of the grid.
+func (m *model) cursorNext() {
+	if m.index+1 >= len(m.visible) && !m.cycle {
+		return
+	}
+	m.index++
+	if m.index >= len(m.visible) {
+		m.index = 0
+	}
+	m.skipGroups(1)
+}
+
+// cursorPrev moves the cursor to the previous item, in reading order of the
+// grid.
+func (m *model) cursorPrev() {
+	if m.index-1 < 0 && !m.cycle {
+		return
+	}
+	m.index--
+	if m.index < 0 {
+		m.index = len(m.visible) - 1
+	}
+	m.skipGroups(-1)
+}
+
+func (m *model) toggleSelection() {
+	if m.limit == 1 || len(m.visible) == 0 {
+		return // no op
+	}
+
+	i := m.visible[m.index]
+	if m.items[i].disabled {
+		return
+	}
+	if m.items[i].selected {
+		m.setSelected(i, false)
+	} else if m.numSelected < m.limit {
+		m.setSelected(i, true)
+	}
+}
+
+// setSelected marks or unmarks the item at index i, keeping track of the
+// number of selected items and the order in which they were selected.
+func (m *model) setSelected(i int, selected bool) {
+	if m.items[i].selected == selected {
+		return
+	}
+	m.items[i].selected = selected
+	if !selected {
+		m.numSelected--
+		return
+	}
+	m.numSelected++
+	m.numSelections++
+	m.items[i].order = m.numSelections
+}
+
+// selectAll selects every visible item, without exceeding the limit.
+func (m *model) selectAll() {
+	if m.limit <= 1 {
+		return
+	}
+	for _, i := range m.visible {
+		if m.numSelected >= m.limit {
+			break // do not exceed given limit
+		}
+		if m.items[i].selected || m.items[i].group || m.items[i].disabled {
+			continue
+		}
+		m.setSelected(i, true)
+	}
+}
+
+// deselectAll deselects every visible item. Selections hidden by the filter
+// are kept.
+func (m *model) deselectAll() {
+	if m.limit <= 1 {
+		return
+	}
+	for _, i := range m.visible {
+		m.setSelected(i, false)
+	}
+}
+
+// updateVisible recomputes which items match the filter query and moves the
+// cursor back to the first of them. Section headers are hidden while a query
+// is active since they would no longer describe the items below them.
+func (m *model) updateVisible() {
+	query := strings.ToLower(m.filter.Value())
+	m.visible = m.visible[:0]
+	for _, i := range m.display {
+		item := m.items[i]
+		if query == "" || (!item.group && strings.Contains(strings.ToLower(item.text), query)) {
+			m.visible = append(m.visible, i)
+		}
+	}
+
+	m.paginator.TotalPages = 1
+	m.paginator.SetTotalPages(len(m.visible))
+	m.index = 0
+	m.skipGroups(1)
+}
+
+// highlighted returns the printed value of the item under the cursor.
+func (m model) highlighted() string {
+	if len(m.visible) == 0 {
+		return ""
+	}
+	return m.items[m.visible[m.index]].output()
+}
+
 func (m model) View() string {
 	if m.quitting {
 		return ""
 	}
+	if !m.preview.Enabled() {
+		return m.listView()
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, m.listView(), m.preview.View())
+}
 
+func (m model) listView() string {
 	var s strings.Builder
 
-	start, end := m.paginator.GetSliceBounds(len(m.items))
-	for i, item := range m.items[start:end] {
-		if i == m.index%m.height {
-			s.WriteString(m.cursorStyle.Render(m.cursor))
-		} else {
-			s.WriteString(strings.Repeat(" ", runewidth.StringWidth(m.cursor)))
+	if m.header != "" {
+		s.WriteString(m.headerStyle.Render(m.header))
+		s.WriteRune('\n')
+		s.WriteString(subduedStyle.Render(m.countView()))
+		s.WriteRune('\n')
+	}
+
+	if m.hasTimeout {
+		countdown := fmt.Sprintf("Choosing in %ds", max(0, int(m.timeout.Seconds())))
+		if m.defaultIndex >= 0 {
+			countdown = fmt.Sprintf("Choosing %q in %ds", m.items[m.defaultIndex].text, max(0, int(m.timeout.Seconds())))
 		}
+		s.WriteString(m.timeoutStyle.Render(countdown))
+		s.WriteRune('\n')
+	}
+
+	if m.filtering || m.filter.Value() != "" {
+		s.WriteString(m.filter.View())
+		s.WriteRune('\n')
+	}
 
-		if item.selected {
-			s.WriteString(m.selectedItemStyle.Render(m.selectedPrefix + item.text))
-		} else if i == m.index%m.height {
-			s.WriteString(m.cursorStyle.Render(m.cursorPrefix + item.text))
-		} else {
-			s.WriteString(m.itemStyle.Render(m.unselectedPrefix + item.text))
+	start, end := m.paginator.GetSliceBounds(len(m.visible))
+	cells := make([]string, 0, end-start)
+	var numbered int
+	for i, idx := range m.visible[start:end] {
+		var number string
+		if m.numbered && !m.items[idx].group {
+			number = "  "
+			if numbered < len(numberKeys) {
+				number = m.numberStyle.Render(numberKeys[numbered]) + " "
+			}
+			numbered++
 		}
-		if i != m.height {
-			s.WriteRune('\n')
+		cells = append(cells, m.renderItem(m.items[idx], i == m.index%m.perPage(), number))
+	}
+
+	// In a grid every column is as wide as the widest cell on the page, so
+	// that the cells line up.
+	var width int
+	if m.columns > 1 {
+		for _, cell := range cells {
+			if w := lipgloss.Width(cell); w > width {
+				width = w
+			}
+		}
+	}
+
+	cellStyle := lipgloss.NewStyle().Width(width + columnGap)
+	for i := 0; i < len(cells); i += m.columns {
+		row := cells[i:min(i+m.columns, len(cells))]
+		for j := 0; j < len(row)-1; j++ {
+			row[j] = cellStyle.Render(row[j])
 		}
+		s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, row...))
+		s.WriteRune('\n')
 	}
 
 	if m.paginator.TotalPages <= 1 {
 		return s.String()
 	}
 
-	s.WriteString(strings.Repeat("\n", m.height-m.paginator.ItemsOnPage(len(m.items))+1))
+	rows := (m.paginator.ItemsOnPage(len(m.visible)) + m.columns - 1) / m.columns
+	s.WriteString(strings.Repeat("\n", (m.rows()-rows)*m.itemLines+1))
 	s.WriteString("  " + m.paginator.View())
 
 	return s.String()
 }
 
+// countView returns the number of visible options and, when picking more than
+// one, the number of selected options.
+func (m model) countView() string {
+	var n int
+	for _, i := range m.visible {
+		if !m.items[i].group {
+			n++
+		}
+	}
+	count := fmt.Sprintf("%d items", n)
+	if m.limit > 1 {
+		count += fmt.Sprintf(", %d selected", m.numSelected)
+	}
+	return count
+}
+
+// renderItem renders a single item of the list, with the cursor if it is the
+// item under the cursor.
+func (m model) renderItem(item item, isCursor bool, number string) string {
+	if item.group {
+		return m.groupStyle.Render(item.text) + strings.Repeat("\n", m.itemLines-1)
+	}
+
+	var s strings.Builder
+
+	if isCursor {
+		s.WriteString(m.cursorStyle.Render(m.cursor))
+	} else {
+		s.WriteString(strings.Repeat(" ", runewidth.StringWidth(m.cursor)))
+	}
+
+	s.WriteString(number)
+
+	prefix, style := m.unselectedPrefix, m.itemStyle
+	if item.disabled {
+		style = m.disabledStyle
+	} else if item.selected {
+		prefix, style = m.selectedPrefix, m.selectedItemStyle
+	} else if isCursor {
+		prefix, style = m.cursorPrefix, m.cursorStyle
+	}
+
+	// Every item takes up exactly the same number of lines, so that the
+	// pagination works out.
+	lines := strings.Split(item.text, "\n")
+	if len(lines) > m.itemLines {
+		lines = lines[:m.itemLines]
+	}
+	for len(lines) < m.itemLines {
+		lines = append(lines, "")
+	}
+
+	s.WriteString(style.Render(prefix + lines[0]))
+	if item.description != "" {
+		s.WriteString("  " + m.descriptionStyle.Render(item.description))
+	}
+
+	indent := strings.Repeat(" ", runewidth.StringWidth(m.cursor)+lipgloss.Width(number)+runewidth.StringWidth(prefix))
+	for _, line := range lines[1:] {
+		s.WriteString("\n" + indent + style.Render(line))
+	}
+
+	return s.String()
+}
+
+// numberedItem returns the index in the visible items of the item on the
+// current page that is selected by the given number key.
+func (m model) numberedItem(key string) (int, bool) {
+	var n int
+	start, end := m.paginator.GetSliceBounds(len(m.visible))
+	for i := start; i < end; i++ {
+		if m.items[m.visible[i]].group {
+			continue
+		}
+		if n < len(numberKeys) && numberKeys[n] == key {
+			return i, true
+		}
+		n++
+	}
+	return 0, false
+}
+
+// moveTo moves the cursor to the item at index i, if it is visible.
+func (m *model) moveTo(i int) {
+	for j, v := range m.visible {
+		if v == i {
+			m.index = j
+			break
+		}
+	}
+	m.paginator.Page = m.index / m.perPage()
+}
+
+// rows returns the number of rows of items displayed on a single page.
+func (m model) rows() int {
+	return max(1, m.height/m.itemLines)
+}
+
+// perPage returns the number of items displayed on a single page.
+func (m model) perPage() int {
+	return m.rows() * m.columns
+}
+
+// skipGroups moves the cursor in the given direction (1 or -1) until it rests
+// on a selectable item, keeping the paginator on the page of the cursor. When
+// the end of the list is reached it either wraps around or, if the list does
+// not cycle, turns back.
+func (m *model) skipGroups(direction int) {
+	n := len(m.visible)
+	if n == 0 {
+		m.index = 0
+		m.paginator.Page = 0
+		return
+	}
+	for i := 0; i < 2*n && m.items[m.visible[m.index]].group; i++ {
+		next := m.index + direction
+		if !m.cycle && (next < 0 || next >= n) {
+			direction = -direction
+			next = m.index + direction
+		}
+		m.index = (next + n) % n
+	}
+	m.paginator.Page = m.index / m.perPage()
+}
+
 //nolint:unparam
 func clamp(x, min, max int) int {
 	if x < min {
@@ -186,3 +627,17 @@ func clamp(x, min, max int) int {
 	}
 	return x
 }
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -3,15 +3,21 @@ package choose
 import (
 	"errors"
 	"fmt"
+	"math/rand"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/charmbracelet/bubbles/paginator"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/charmbracelet/gum/internal/exit"
+	"github.com/charmbracelet/gum/internal/preview"
 	"github.com/charmbracelet/gum/internal/stdin"
 	"github.com/charmbracelet/gum/style"
 )
@@ -29,7 +35,11 @@ func (o Options) Run() error {
 		if input == "" {
 			return errors.New("no options provided, see `gum choose --help`")
 		}
-		o.Options = strings.Split(strings.TrimSpace(input), "\n")
+		if o.Read0 {
+			o.Options = strings.Split(strings.TrimSuffix(input, "\x00"), "\x00")
+		} else {
+			o.Options = strings.Split(strings.TrimSpace(input), "\n")
+		}
 	}
 
 	// We don't need to display prefixes if we are only picking one option.
@@ -46,27 +56,85 @@ func (o Options) Run() error {
 		o.Limit = len(o.Options)
 	}
 
-	// Keep track of the selected items.
-	currentSelected := 0
-	// Check if selected items should be used.
-	hasSelectedItems := o.Limit > 1 && len(o.Selected) > 0
-
 	var items = make([]item, len(o.Options))
+	var numGroups int
 	for i, option := range o.Options {
-		// Check if the option should be selected.
-		isSelected := hasSelectedItems && currentSelected < o.Limit && arrayContains(o.Selected, option)
-		// If the option is selected then increment the current selected count.
-		if isSelected {
-			currentSelected++
+		// Lines starting with the group prefix are section headers, which are
+		// shown in the list but are not options themselves.
+		if o.GroupPrefix != "" && strings.HasPrefix(option, o.GroupPrefix) {
+			items[i] = item{text: strings.TrimPrefix(option, o.GroupPrefix), group: true}
+			numGroups++
+			continue
+		}
+
+		// Everything after the first tab is a description, which is only
+		// displayed next to the option and never printed.
+		var description string
+		if o.Descriptions {
+			if parts := strings.SplitN(option, "\t", 2); len(parts) == 2 {
+				option, description = parts[0], parts[1]
+			}
+		}
+
+		// Split the option into the label that is displayed and the value
+		// that is printed when it is chosen.
+		var value string
+		if o.LabelDelimiter != "" {
+			if parts := strings.SplitN(option, o.LabelDelimiter, 2); len(parts) == 2 {
+				option, value = parts[0], parts[1]
+			}
+		}
+
+		items[i] = item{text: option, value: value, description: description}
+	}
+
+	if numGroups == len(items) {
+		return errors.New("no options provided, see `gum choose --help`")
+	}
+
+	// The items are displayed in the order they were given, unless they are
+	// shuffled. In that case the options are only shuffled within their
+	// groups so that the section headers still make sense.
+	display := make([]int, len(items))
+	for i := range items {
+		display[i] = i
+	}
+	if o.Shuffle {
+		r := rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec
+		start := 0
+		for end := 0; end <= len(items); end++ {
+			if end < len(items) && !items[end].group {
+				continue
+			}
+			group := display[start:end]
+			r.Shuffle(len(group), func(i, j int) {
+				group[i], group[j] = group[j], group[i]
+			})
+			start = end + 1
 		}
-		items[i] = item{text: option, selected: isSelected}
+	}
+
+	// Initially every item is visible, until the user applies a filter.
+	visible := make([]int, len(display))
+	copy(visible, display)
+
+	filter := textinput.New()
+	filter.Prompt = o.FilterPrompt
+	filter.PromptStyle = o.FilterPromptStyle.ToLipgloss()
+	filter.Placeholder = o.FilterPlaceholder
+
+	if o.Columns < 1 {
+		o.Columns = 1
+	}
+	if o.ItemLines < 1 {
+		o.ItemLines = 1
 	}
 
 	// Use the pagination model to display the current and total number of
 	// pages.
 	pager := paginator.New()
-	pager.SetTotalPages((len(items) + o.Height - 1) / o.Height)
-	pager.PerPage = o.Height
+	pager.PerPage = max(1, o.Height/o.ItemLines) * o.Columns
+	pager.SetTotalPages(len(items))
 	pager.Type = paginator.Dots
 	pager.ActiveDot = subduedStyle.Render("•")
 	pager.InactiveDot = verySubduedStyle.Render("•")
@@ -77,37 +145,127 @@ func (o Options) Run() error {
 	pager.UseJKKeys = false
 	pager.UsePgUpPgDownKeys = false
 
-	tm, err := tea.NewProgram(model{
+	m := model{
+		header:            o.Header,
+		headerStyle:       o.HeaderStyle.ToLipgloss(),
 		height:            o.Height,
+		columns:           o.Columns,
+		itemLines:         o.ItemLines,
+		cycle:             o.Cycle,
+		numbered:          o.Numbered,
 		cursor:            o.Cursor,
 		selectedPrefix:    o.SelectedPrefix,
 		unselectedPrefix:  o.UnselectedPrefix,
 		cursorPrefix:      o.CursorPrefix,
 		items:             items,
+		display:           display,
+		visible:           visible,
+		filter:            filter,
 		limit:             o.Limit,
 		paginator:         pager,
 		cursorStyle:       o.CursorStyle.ToLipgloss(),
 		itemStyle:         o.ItemStyle.ToLipgloss(),
 		selectedItemStyle: o.SelectedItemStyle.ToLipgloss(),
-		numSelected:       currentSelected,
-	}, tea.WithOutput(os.Stderr)).StartReturningModel()
+		groupStyle:        o.GroupStyle.ToLipgloss(),
+		descriptionStyle:  o.DescriptionStyle.ToLipgloss(),
+		disabledStyle:     o.DisabledStyle.ToLipgloss(),
+		numberStyle:       o.NumberStyle.ToLipgloss(),
+		timeoutStyle:      o.TimeoutStyle.ToLipgloss(),
+		timeout:           o.Timeout,
+		hasTimeout:        o.Timeout > 0,
+		defaultIndex:      -1,
+	}
+
+	for _, i := range matchOptions(items, o.Disabled) {
+		m.items[i].disabled = true
+	}
+
+	// When picking a single option the cursor starts on the first of the
+	// selected options, otherwise they are all marked as selected.
+	for _, i := range matchOptions(items, o.Selected) {
+		if o.Limit == 1 {
+			m.moveTo(i)
+			break
+		}
+		if m.numSelected >= m.limit {
+			break // do not exceed given limit
+		}
+		if !m.items[i].disabled {
+			m.setSelected(i, true)
+		}
+	}
+
+	if o.SelectAll {
+		m.selectAll()
+	}
 
+	m.preview = preview.New(o.Preview, m.highlighted())
+	m.preview.Width = o.PreviewWidth
+	m.preview.Height = o.Height
+	m.preview.Style = o.PreviewStyle.ToLipgloss()
+
+	if o.Default != "" {
+		matches := matchOptions(items, []string{o.Default})
+		if len(matches) == 0 {
+			return fmt.Errorf("default option %q is not one of the options", o.Default)
+		}
+		// Disabled options can not be chosen, not even once the timeout runs out.
+		for _, i := range matches {
+			if !m.items[i].disabled {
+				m.defaultIndex = i
+				break
+			}
+		}
+		if m.defaultIndex < 0 {
+			return fmt.Errorf("default option %q is disabled", o.Default)
+		}
+	}
+
+	// Make sure the cursor does not start on a section header.
+	m.skipGroups(1)
+
+	tm, err := tea.NewProgram(m, tea.WithOutput(os.Stderr)).StartReturningModel()
 	if err != nil {
 		return fmt.Errorf("failed to start tea program: %w", err)
 	}
 
-	m := tm.(model)
+	m = tm.(model)
 	if m.aborted {
 		return exit.ErrAborted
 	}
 
+	var selected []int
+	for i, item := range m.items {
+		if item.selected {
+			selected = append(selected, i)
+		}
+	}
+
+	// The items are printed in the order they were given, unless the user
+	// wants them in the order they were selected.
+	if o.Ordered {
+		sort.SliceStable(selected, func(i, j int) bool {
+			return m.items[selected[i]].order < m.items[selected[j]].order
+		})
+	}
+
+	separator := '\n'
+	if o.Print0 {
+		separator = '\x00'
+	}
+
 	var s strings.Builder
 
-	for _, item := range m.items {
-		if item.selected {
-			s.WriteString(item.text)
-			s.WriteRune('\n')
+	for _, i := range selected {
+		switch o.Output {
+		case "index":
+			s.WriteString(strconv.Itoa(optionIndex(m.items, i)))
+		case "both":
+			s.WriteString(strconv.Itoa(optionIndex(m.items, i)) + "\t" + m.items[i].output())
+		default:
+			s.WriteString(m.items[i].output())
 		}
+		s.WriteRune(separator)
 	}
 
 	fmt.Print(s.String())
@@ -121,12 +279,55 @@ func (o Options) BeforeReset(ctx *kong.Context) error {
 	return nil
 }
 
-// Check if an array contains a value.
-func arrayContains(strArray []string, value string) bool {
-	for _, str := range strArray {
-		if str == value {
-			return true
+// matchOptions returns the indexes of the items matching the given values, in
+// the order of the values. A value matches an item by its text or its printed
+// value, or else by its zero-based position among the options if the value is
+// a number. Section headers are not options, so they are not counted.
+func matchOptions(items []item, values []string) []int {
+	var matches []int
+	for _, value := range values {
+		var found bool
+		for i, item := range items {
+			if !item.group && (item.text == value || item.value == value) {
+				matches = append(matches, i)
+				found = true
+			}
+		}
+		if found {
+			continue
+		}
+		if n, err := strconv.Atoi(value); err == nil {
+			if i := itemIndex(items, n); i >= 0 {
+				matches = append(matches, i)
+			}
+		}
+	}
+	return matches
+}
+
+// optionIndex returns the zero-based position of the item among the options,
+// the same way as options are matched by their index.
+func optionIndex(items []item, i int) int {
+	var n int
+	for _, item := range items[:i] {
+		if !item.group {
+			n++
+		}
+	}
+	return n
+}
+
+// itemIndex returns the index of the item that is the n-th option, or -1 if
+// there are not as many options.
+func itemIndex(items []item, n int) int {
+	for i, item := range items {
+		if item.group {
+			continue
+		}
+		if n == 0 {
+			return i
 		}
+		n--
 	}
-	return false
+	return -1
 }
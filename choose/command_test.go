@@ -0,0 +1,55 @@
+package choose
+
+import (
+	"reflect"
+	"testing"
+)
+
+// options returns the items, where those that end with a colon are section
+// headers.
+func options(texts ...string) []item {
+	items := make([]item, len(texts))
+	for i, text := range texts {
+		items[i] = item{text: text, group: text[len(text)-1] == ':'}
+	}
+	return items
+}
+
+func TestMatchOptions(t *testing.T) {
+	items := options("Fruits:", "apple", "banana", "Vegetables:", "carrot", "2")
+	items[2].value = "b"
+
+	for _, tt := range []struct {
+		values []string
+		want   []int
+	}{
+		{[]string{"apple"}, []int{1}},
+		{[]string{"b"}, []int{2}},
+		{[]string{"carrot", "apple"}, []int{4, 1}},
+		// Indexes count the options only, not the section headers.
+		{[]string{"0", "1"}, []int{1, 2}},
+		// The text of an option comes before its index.
+		{[]string{"2"}, []int{5}},
+		{[]string{"Fruits:"}, nil},
+		{[]string{"cherry", "9", "-1"}, nil},
+	} {
+		if got := matchOptions(items, tt.values); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("matchOptions(%q) = %v, want %v", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestOptionIndex(t *testing.T) {
+	items := options("Fruits:", "apple", "banana", "Vegetables:", "carrot")
+	for i, want := range map[int]int{1: 0, 2: 1, 4: 2} {
+		if got := optionIndex(items, i); got != want {
+			t.Errorf("optionIndex(%d) = %d, want %d", i, got, want)
+		}
+		if got := itemIndex(items, want); got != i {
+			t.Errorf("itemIndex(%d) = %d, want %d", want, got, i)
+		}
+	}
+	if got := itemIndex(items, 3); got != -1 {
+		t.Errorf("itemIndex(3) = %d, want -1", got)
+	}
+}
@@ -1,20 +1,54 @@
 package choose
 
-import "github.com/charmbracelet/gum/style"
+import (
+	"time"
+
+	"github.com/charmbracelet/gum/style"
+)
 
 // Options is the customization options for the choose command.
 type Options struct {
 	Options []string `arg:"" optional:"" help:"Options to choose from."`
 
-	Limit             int          `help:"Maximum number of options to pick" default:"1" group:"Selection"`
-	NoLimit           bool         `help:"Pick unlimited number of options (ignores limit)" group:"Selection"`
-	Height            int          `help:"Height of the list" default:"10" env:"GUM_CHOOSE_HEIGHT"`
-	Cursor            string       `help:"Prefix to show on item that corresponds to the cursor position" default:"> " env:"GUM_CHOOSE_CURSOR"`
-	CursorPrefix      string       `help:"Prefix to show on the cursor item (hidden if limit is 1)" default:"○ " env:"GUM_CHOOSE_CURSOR_PREFIX"`
-	SelectedPrefix    string       `help:"Prefix to show on selected items (hidden if limit is 1)" default:"◉ " env:"GUM_CHOOSE_SELECTED_PREFIX"`
-	UnselectedPrefix  string       `help:"Prefix to show on unselected items (hidden if limit is 1)" default:"○ " env:"GUM_CHOOSE_UNSELECTED_PREFIX"`
-	Selected          []string     `help:"Options that should start as selected" default:"" env:"GUM_CHOOSE_SELECTED"`
-	CursorStyle       style.Styles `embed:"" prefix:"cursor." set:"defaultForeground=212" envprefix:"GUM_CHOOSE_CURSOR_"`
-	ItemStyle         style.Styles `embed:"" prefix:"item." hidden:"" envprefix:"GUM_CHOOSE_ITEM_"`
-	SelectedItemStyle style.Styles `embed:"" prefix:"selected." set:"defaultForeground=212" envprefix:"GUM_CHOOSE_SELECTED_"`
+	Read0             bool          `name:"read0" help:"Read NUL-separated options from stdin instead of new-line separated" default:"false" env:"GUM_CHOOSE_READ0"`
+	Print0            bool          `name:"print0" help:"Separate the selected options with NUL instead of new-lines" default:"false" env:"GUM_CHOOSE_PRINT0"`
+	Limit             int           `help:"Maximum number of options to pick" default:"1" group:"Selection"`
+	NoLimit           bool          `help:"Pick unlimited number of options (ignores limit)" group:"Selection"`
+	Header            string        `help:"Header value, displayed above the list with the number of options" default:"" env:"GUM_CHOOSE_HEADER"`
+	Height            int           `help:"Height of the list, in lines" default:"10" env:"GUM_CHOOSE_HEIGHT"`
+	Shuffle           bool          `help:"Display the options in a random order (the output keeps the given order)" default:"false" env:"GUM_CHOOSE_SHUFFLE"`
+	Cycle             bool          `help:"Wrap around when moving past the first or last option" default:"true" negatable:"" env:"GUM_CHOOSE_CYCLE"`
+	ItemLines         int           `help:"Number of lines each option takes up in the list" default:"1" env:"GUM_CHOOSE_ITEM_LINES"`
+	Columns           int           `help:"Number of columns to lay the options out in" default:"1" env:"GUM_CHOOSE_COLUMNS"`
+	Cursor            string        `help:"Prefix to show on item that corresponds to the cursor position" default:"> " env:"GUM_CHOOSE_CURSOR"`
+	CursorPrefix      string        `help:"Prefix to show on the cursor item (hidden if limit is 1)" default:"○ " env:"GUM_CHOOSE_CURSOR_PREFIX"`
+	SelectedPrefix    string        `help:"Prefix to show on selected items (hidden if limit is 1)" default:"◉ " env:"GUM_CHOOSE_SELECTED_PREFIX"`
+	UnselectedPrefix  string        `help:"Prefix to show on unselected items (hidden if limit is 1)" default:"○ " env:"GUM_CHOOSE_UNSELECTED_PREFIX"`
+	Output            string        `help:"Print the text, the zero-based index among the options or both (tab-separated) of the selected options" enum:"text,index,both" default:"text" env:"GUM_CHOOSE_OUTPUT"`
+	Ordered           bool          `help:"Print the selected options in the order they were selected" default:"false" group:"Selection" env:"GUM_CHOOSE_ORDERED"`
+	SelectAll         bool          `help:"Start with all options selected (ignored if limit is 1)" default:"false" group:"Selection" env:"GUM_CHOOSE_SELECT_ALL"`
+	Selected          []string      `help:"Options that should start as selected, by text or zero-based index among the options" default:"" env:"GUM_CHOOSE_SELECTED"`
+	Numbered          bool          `help:"Number the options on each page and pick them with the keys 1-9 and 0" default:"false" env:"GUM_CHOOSE_NUMBERED"`
+	Timeout           time.Duration `help:"Timeout after which the default option is chosen" default:"0" env:"GUM_CHOOSE_TIMEOUT"`
+	Default           string        `help:"Option to choose when the timeout runs out, by text or zero-based index among the options (it must not be disabled)" default:"" env:"GUM_CHOOSE_DEFAULT"`
+	Disabled          []string      `help:"Options that are displayed but cannot be selected, by text or zero-based index among the options" default:"" env:"GUM_CHOOSE_DISABLED"`
+	GroupPrefix       string        `help:"Prefix marking lines that are section headers rather than options" default:"" env:"GUM_CHOOSE_GROUP_PREFIX"`
+	Preview           string        `help:"Command to preview the highlighted option with, {} is replaced by the option" default:"" env:"GUM_CHOOSE_PREVIEW"`
+	PreviewWidth      int           `help:"Width of the preview pane" default:"60" env:"GUM_CHOOSE_PREVIEW_WIDTH"`
+	LabelDelimiter    string        `help:"Delimiter separating the displayed label from the printed value of an option" default:"" env:"GUM_CHOOSE_LABEL_DELIMITER"`
+	Descriptions      bool          `help:"Display the text after a tab as a description of the option" default:"false" env:"GUM_CHOOSE_DESCRIPTIONS"`
+	HeaderStyle       style.Styles  `embed:"" prefix:"header." set:"defaultForeground=99" envprefix:"GUM_CHOOSE_HEADER_"`
+	CursorStyle       style.Styles  `embed:"" prefix:"cursor." set:"defaultForeground=212" envprefix:"GUM_CHOOSE_CURSOR_"`
+	ItemStyle         style.Styles  `embed:"" prefix:"item." hidden:"" envprefix:"GUM_CHOOSE_ITEM_"`
+	SelectedItemStyle style.Styles  `embed:"" prefix:"selected." set:"defaultForeground=212" envprefix:"GUM_CHOOSE_SELECTED_"`
+	FilterPrompt      string        `help:"Prompt to display when filtering with /" default:"/" env:"GUM_CHOOSE_FILTER_PROMPT"`
+	FilterPlaceholder string        `help:"Placeholder value when filtering with /" default:"Filter..." env:"GUM_CHOOSE_FILTER_PLACEHOLDER"`
+	FilterPromptStyle style.Styles  `embed:"" prefix:"filter-prompt." set:"defaultForeground=240" envprefix:"GUM_CHOOSE_FILTER_PROMPT_"`
+	DisabledStyle     style.Styles  `embed:"" prefix:"disabled." set:"defaultForeground=240" envprefix:"GUM_CHOOSE_DISABLED_"`
+	NumberStyle       style.Styles  `embed:"" prefix:"number." set:"defaultForeground=240" envprefix:"GUM_CHOOSE_NUMBER_"`
+	TimeoutStyle      style.Styles  `embed:"" prefix:"timeout." set:"defaultForeground=240" envprefix:"GUM_CHOOSE_TIMEOUT_"`
+	PreviewStyle      style.Styles  `embed:"" prefix:"preview." set:"defaultMargin=0 0 0 2" envprefix:"GUM_CHOOSE_PREVIEW_"`
+	DescriptionStyle  style.Styles  `embed:"" prefix:"description." set:"defaultForeground=240" envprefix:"GUM_CHOOSE_DESCRIPTION_"`
+	//nolint:staticcheck
+	GroupStyle style.Styles `embed:"" prefix:"group." set:"defaultForeground=99" set:"defaultUnderline=true" envprefix:"GUM_CHOOSE_GROUP_"`
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/alecthomas/kong"
@@ -12,8 +13,10 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/sahilm/fuzzy"
 
+	"github.com/charmbracelet/gum/internal/ansi"
 	"github.com/charmbracelet/gum/internal/exit"
 	"github.com/charmbracelet/gum/internal/files"
+	"github.com/charmbracelet/gum/internal/preview"
 	"github.com/charmbracelet/gum/internal/stdin"
 	"github.com/charmbracelet/gum/style"
 )
@@ -41,31 +44,70 @@ func (o Options) Run() error {
 		choices = files.List()
 	}
 
-	if len(choices) == 0 {
+	cs := o.prepare(choices)
+	if len(cs.lines) == 0 {
+		if o.ExitIfEmpty {
+			return exit.ErrNoMatch
+		}
 		return errors.New("no options provided, see `gum filter --help`")
 	}
 
+	reload, err := parseBindings(o.Bind)
+	if err != nil {
+		return err
+	}
+
 	options := []tea.ProgramOption{tea.WithOutput(os.Stderr)}
 	if o.Height == 0 {
 		options = append(options, tea.WithAltScreen())
 	}
 
+	matcher := matcher{mode: o.Match, caseSensitivity: o.Case, sort: o.Sort}
+	matcher.setChoices(cs.matched)
+
 	var matches []fuzzy.Match
+	if o.Query != "" {
+		o.Value = o.Query
+	}
+
 	if o.Value != "" {
 		i.SetValue(o.Value)
-		matches = fuzzy.Find(o.Value, choices)
+		i.CursorEnd()
+		matches = matcher.find(o.Value)
 	} else {
-		matches = matchAll(choices)
+		matches = matchAll(cs.matched)
+	}
+
+	if o.ExitIfEmpty && len(matches) == 0 {
+		return exit.ErrNoMatch
+	}
+
+	if o.SelectIfOne && len(matches) == 1 {
+		fmt.Println(cs.lines[matches[0].Index])
+		return nil
 	}
 
 	if o.NoLimit {
-		o.Limit = len(choices)
+		o.Limit = len(cs.lines)
+	}
+
+	var highlighted string
+	if len(matches) > 0 {
+		highlighted = cs.lines[matches[0].Index]
 	}
+	pv := preview.New(o.Preview, highlighted)
+	pv.Width = o.PreviewWidth
+	pv.Style = o.PreviewStyle.ToLipgloss()
 
 	p := tea.NewProgram(model{
-		choices:               choices,
+		display:               cs.display,
+		lines:                 cs.lines,
+		header:                cs.header,
+		prepare:               o.prepare,
+		reload:                reload,
 		indicator:             o.Indicator,
 		matches:               matches,
+		matcher:               matcher,
 		textinput:             i,
 		viewport:              &v,
 		indicatorStyle:        o.IndicatorStyle.ToLipgloss(),
@@ -76,8 +118,13 @@ func (o Options) Run() error {
 		matchStyle:            o.MatchStyle.ToLipgloss(),
 		textStyle:             o.TextStyle.ToLipgloss(),
 		height:                o.Height,
-		selected:              make(map[string]struct{}),
+		headerStyle:           o.HeaderStyle.ToLipgloss(),
+		errorStyle:            o.ErrorStyle.ToLipgloss(),
+		reverse:               o.Layout == "reverse",
+		selected:              make(map[int]struct{}),
 		limit:                 o.Limit,
+		noLimit:               o.NoLimit,
+		preview:               pv,
 	}, options...)
 
 	tm, err := p.StartReturningModel()
@@ -90,20 +137,117 @@ func (o Options) Run() error {
 		return exit.ErrAborted
 	}
 
+	// Nothing matched, so the query itself is the answer if the script asked
+	// for it. The exit status tells it apart from a chosen match.
+	if o.PrintQuery && len(m.selected) == 0 && len(m.matches) == 0 {
+		fmt.Println(m.textinput.Value())
+		return exit.ErrNoMatch
+	}
+
 	// allSelections contains values only if limit is greater
 	// than 1 or if flag --no-limit is passed, hence there is
 	// no need to further checks
 	if len(m.selected) > 0 {
+		selected := make([]int, 0, len(m.selected))
 		for k := range m.selected {
-			fmt.Println(k)
+			selected = append(selected, k)
+		}
+		sort.Ints(selected)
+		for _, k := range selected {
+			fmt.Println(m.lines[k])
 		}
 	} else if len(m.matches) > m.cursor && m.cursor >= 0 {
-		fmt.Println(m.matches[m.cursor].Str)
+		fmt.Println(m.lines[m.matches[m.cursor].Index])
 	}
 
 	return nil
 }
 
+// choiceSet holds the lines of the input, prepared for display and matching.
+type choiceSet struct {
+	// header lines are displayed above the options but can't be chosen.
+	header []string
+	// lines are printed when chosen.
+	lines []string
+	// matched are matched against the filter.
+	matched []string
+	// display, if set, is displayed instead of matched.
+	display []string
+}
+
+// prepare splits the header off the input and prepares the remaining lines to
+// be matched and displayed.
+func (o Options) prepare(input []string) choiceSet {
+	var cs choiceSet
+
+	// The first lines may be a header, such as the column names of a table,
+	// which is displayed above the options but can't be chosen.
+	if o.HeaderLines > 0 {
+		n := o.HeaderLines
+		if n > len(input) {
+			n = len(input)
+		}
+		cs.header, input = input[:n], input[n:]
+	}
+
+	// History-like inputs have their newest lines last, reverse them so
+	// that those come first.
+	if o.Tac {
+		reversed := make([]string, len(input))
+		for i, line := range input {
+			reversed[len(input)-1-i] = line
+		}
+		input = reversed
+	}
+
+	// The lines are printed when chosen. With ANSI colors they are printed
+	// (and matched) without their escape sequences.
+	cs.lines = input
+	if o.ANSI {
+		cs.lines = make([]string, len(input))
+		for i, choice := range input {
+			cs.lines[i] = ansi.Strip(choice)
+		}
+	}
+
+	// Only the chosen fields of the lines are matched against the filter,
+	// and displayed. Unless told otherwise, the fields that are displayed
+	// are matched, so that nothing that can't be seen matches.
+	nth := o.Nth
+	if len(nth) == 0 {
+		nth = o.WithNth
+	}
+	cs.matched = cs.lines
+	if len(nth) > 0 {
+		cs.matched = make([]string, len(cs.lines))
+		for i, line := range cs.lines {
+			cs.matched[i] = fields(line, o.Delimiter, nth)
+		}
+	}
+	if o.ANSI || len(o.Nth) > 0 || len(o.WithNth) > 0 {
+		cs.display = make([]string, len(input))
+		for i, choice := range input {
+			cs.display[i] = fields(choice, o.Delimiter, o.WithNth)
+		}
+	}
+
+	return cs
+}
+
+// parseBindings parses key bindings of the form KEY:reload(COMMAND) into a map
+// of keys to the commands that reload the options.
+func parseBindings(bindings []string) (map[string]string, error) {
+	reload := make(map[string]string, len(bindings))
+	for _, binding := range bindings {
+		parts := strings.SplitN(binding, ":", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[1], "reload(") || !strings.HasSuffix(parts[1], ")") {
+			return nil, fmt.Errorf("invalid binding %q, expected KEY:reload(COMMAND)", binding)
+		}
+		reload[parts[0]] = strings.TrimSuffix(strings.TrimPrefix(parts[1], "reload("), ")")
+	}
+	return reload, nil
+}
+
 // BeforeReset hook. Used to unclutter style flags.
 func (o Options) BeforeReset(ctx *kong.Context) error {
 	style.HideFlags(ctx)
@@ -0,0 +1,95 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrepare(t *testing.T) {
+	input := []string{"NAME PID", "vim 12", "\x1b[1mgo\x1b[0m 34"}
+
+	for _, tt := range []struct {
+		name string
+		o    Options
+		want choiceSet
+	}{
+		{
+			name: "plain",
+			o:    Options{},
+			want: choiceSet{lines: input, matched: input},
+		},
+		{
+			name: "header",
+			o:    Options{HeaderLines: 1},
+			want: choiceSet{header: input[:1], lines: input[1:], matched: input[1:]},
+		},
+		{
+			name: "tac",
+			o:    Options{HeaderLines: 1, Tac: true},
+			want: choiceSet{header: input[:1], lines: []string{input[2], input[1]}, matched: []string{input[2], input[1]}},
+		},
+		{
+			name: "ansi",
+			o:    Options{HeaderLines: 1, ANSI: true},
+			want: choiceSet{
+				header:  input[:1],
+				lines:   []string{"vim 12", "go 34"},
+				matched: []string{"vim 12", "go 34"},
+				display: []string{"vim 12", "\x1b[1mgo\x1b[0m 34"},
+			},
+		},
+		{
+			name: "nth",
+			o:    Options{HeaderLines: 1, ANSI: true, Nth: []int{-1}},
+			want: choiceSet{
+				header:  input[:1],
+				lines:   []string{"vim 12", "go 34"},
+				matched: []string{"12", "34"},
+				display: []string{"vim 12", "\x1b[1mgo\x1b[0m 34"},
+			},
+		},
+		{
+			// The displayed fields are matched unless told otherwise.
+			name: "with-nth",
+			o:    Options{HeaderLines: 1, WithNth: []int{1}},
+			want: choiceSet{
+				header:  input[:1],
+				lines:   input[1:],
+				matched: []string{"vim", "\x1b[1mgo\x1b[0m"},
+				display: []string{"vim", "\x1b[1mgo\x1b[0m"},
+			},
+		},
+		{
+			name: "nth and with-nth",
+			o:    Options{HeaderLines: 1, ANSI: true, Nth: []int{2}, WithNth: []int{1}},
+			want: choiceSet{
+				header:  input[:1],
+				lines:   []string{"vim 12", "go 34"},
+				matched: []string{"12", "34"},
+				display: []string{"vim", "\x1b[1mgo\x1b[0m"},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.o.prepare(input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("prepare() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBindings(t *testing.T) {
+	got, err := parseBindings([]string{"ctrl+r:reload(ls -a)", "f5:reload(echo a:b)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"ctrl+r": "ls -a", "f5": "echo a:b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseBindings() = %q, want %q", got, want)
+	}
+
+	for _, binding := range []string{"ctrl+r", "ctrl+r:ls", "ctrl+r:reload(ls"} {
+		if _, err := parseBindings([]string{binding}); err == nil {
+			t.Errorf("parseBindings(%q) did not fail", binding)
+		}
+	}
+}
@@ -11,6 +11,10 @@
 package filter
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -19,21 +23,33 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mattn/go-runewidth"
 	"github.com/sahilm/fuzzy"
+
+	"github.com/charmbracelet/gum/internal/preview"
 )
 
 type model struct {
 	textinput             textinput.Model
 	viewport              *viewport.Model
-	choices               []string
+	display               []string
+	lines                 []string
+	prepare               func([]string) choiceSet
+	reload                map[string]string
 	matches               []fuzzy.Match
+	matcher               matcher
+	matchID               int
+	cancelMatch           context.CancelFunc
 	cursor                int
-	selected              map[string]struct{}
+	offset                int
+	selected              map[int]struct{}
 	limit                 int
+	noLimit               bool
 	numSelected           int
 	indicator             string
 	selectedPrefix        string
 	unselectedPrefix      string
 	height                int
+	header                []string
+	reverse               bool
 	aborted               bool
 	quitting              bool
 	matchStyle            lipgloss.Style
@@ -41,9 +57,15 @@ type model struct {
 	indicatorStyle        lipgloss.Style
 	selectedPrefixStyle   lipgloss.Style
 	unselectedPrefixStyle lipgloss.Style
+	headerStyle           lipgloss.Style
+	preview               preview.Model
+
+	// err holds why the options could not be reloaded, until they are.
+	err        string
+	errorStyle lipgloss.Style
 }
 
-func (m model) Init() tea.Cmd { return nil }
+func (m model) Init() tea.Cmd { return m.preview.Init() }
 func (m model) View() string {
 	if m.quitting {
 		return ""
@@ -52,8 +74,11 @@ func (m model) View() string {
 	var s strings.Builder
 
 	// Since there are matches, display them so that the user can see, in real
-	// time, what they are searching for.
-	for i, match := range m.matches {
+	// time, what they are searching for. Only the matches in view are
+	// rendered, there may be a great many of them.
+	end := min(len(m.matches), m.offset+m.viewport.Height)
+	for i := m.offset; i < end; i++ {
+		match := m.matches[i]
 		// If this is the current selected index, we add a small indicator to
 		// represent it. Otherwise, simply pad the string.
 		if i == m.cursor {
@@ -63,7 +88,7 @@ func (m model) View() string {
 		}
 
 		// If there are multiple selections mark them, otherwise leave an empty space
-		if _, ok := m.selected[match.Str]; ok {
+		if _, ok := m.selected[match.Index]; ok {
 			s.WriteString(m.selectedPrefixStyle.Render(m.selectedPrefix))
 		} else if m.limit > 1 {
 			s.WriteString(m.unselectedPrefixStyle.Render(m.unselectedPrefix))
@@ -71,23 +96,19 @@ func (m model) View() string {
 			s.WriteString(" ")
 		}
 
+		// Choices that are displayed differently from how they are matched,
+		// i.e. with colors of their own or other fields, are displayed as
+		// they are.
+		if m.display != nil && m.display[match.Index] != match.Str {
+			s.WriteString(m.display[match.Index])
+			s.WriteRune('\n')
+			continue
+		}
+
 		// For this match, there are a certain number of characters that have
 		// caused the match. i.e. fuzzy matching.
 		// We should indicate to the users which characters are being matched.
-		var mi = 0
-		for ci, c := range match.Str {
-			// Check if the current character index matches the current matched
-			// index. If so, color the character to indicate a match.
-			if mi < len(match.MatchedIndexes) && ci == match.MatchedIndexes[mi] {
-				s.WriteString(m.matchStyle.Render(string(c)))
-				// We have matched this character, so we never have to check it
-				// again. Move on to the next match.
-				mi++
-			} else {
-				// Not a match, simply show the character, unstyled.
-				s.WriteString(m.textStyle.Render(string(c)))
-			}
-		}
+		s.WriteString(m.highlight(match))
 
 		// We have finished displaying the match with all of it's matched
 		// characters highlighted and the rest filled in.
@@ -95,21 +116,169 @@ func (m model) View() string {
 		s.WriteRune('\n')
 	}
 
-	m.viewport.SetContent(s.String())
+	content := strings.TrimSuffix(s.String(), "\n")
+	m.viewport.SetContent(content)
+
+	list := m.viewport.View()
+	if m.reverse {
+		list = m.reverseView(content)
+	}
+
+	if m.preview.Enabled() {
+		m.preview.Height = m.viewport.Height
+		list = lipgloss.JoinHorizontal(lipgloss.Top, list, m.preview.View())
+	}
+
+	// The header sits between the input and the matches, indented to line
+	// up with them.
+	if len(m.header) > 0 {
+		header := m.headerView()
+		if m.reverse {
+			list = list + "\n" + header
+		} else {
+			list = header + "\n" + list
+		}
+	}
 
 	// View the input and the filtered choices
-	return m.textinput.View() + "\n" + m.viewport.View()
+	input := m.textinput.View()
+	if m.err != "" {
+		input += " " + m.errorStyle.Render(m.err)
+	}
+	if m.reverse {
+		return list + "\n" + input
+	}
+	return input + "\n" + list
+}
+
+// highlight renders the characters of a match that caused it in the match
+// style and the rest in the text style. Consecutive characters of the same
+// kind are rendered together, so that styles apply to them as a whole.
+func (m model) highlight(match fuzzy.Match) string {
+	var s, run strings.Builder
+	var matched bool
+	render := func() {
+		style := m.textStyle
+		if matched {
+			style = m.matchStyle
+		}
+		s.WriteString(style.Render(run.String()))
+		run.Reset()
+	}
+
+	var mi = 0
+	for ci, c := range match.Str {
+		// The matched indexes are those of the first byte of each matched
+		// character, skip any that are not so they can't hold up the rest.
+		for mi < len(match.MatchedIndexes) && match.MatchedIndexes[mi] < ci {
+			mi++
+		}
+		isMatch := mi < len(match.MatchedIndexes) && match.MatchedIndexes[mi] == ci
+		if isMatch != matched && run.Len() > 0 {
+			render()
+		}
+		matched = isMatch
+		run.WriteRune(c)
+	}
+	if run.Len() > 0 {
+		render()
+	}
+
+	return s.String()
+}
+
+// headerView renders the header lines, indented by the width of the indicator
+// and prefix of the matches.
+func (m model) headerView() string {
+	indent := runewidth.StringWidth(m.indicator) + 1
+	if m.limit > 1 {
+		indent = runewidth.StringWidth(m.indicator) + runewidth.StringWidth(m.unselectedPrefix)
+	}
+
+	lines := make([]string, len(m.header))
+	for i, line := range m.header {
+		lines[i] = strings.Repeat(" ", indent) + m.headerStyle.Render(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// reverseView displays the lines of the matches that are scrolled into view
+// bottom to top, so that the first match sits right above the input.
+func (m model) reverseView(content string) string {
+	var lines []string
+	if content != "" {
+		lines = strings.Split(content, "\n")
+	}
+
+	view := make([]string, 0, m.viewport.Height)
+	for i := len(lines); i < m.viewport.Height; i++ {
+		view = append(view, "")
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		view = append(view, lines[i])
+	}
+	return strings.Join(view, "\n")
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if !m.preview.Enabled() {
+		return m.update(msg)
+	}
+
+	var previewCmd tea.Cmd
+	m.preview, previewCmd = m.preview.Update(msg)
+	tm, cmd := m.update(msg)
+	m = tm.(model)
+
+	// The cursor or the matches may have changed, so the preview has to
+	// follow the highlighted match.
+	highlightCmd := m.preview.SetItem(m.highlighted())
+	return m, tea.Batch(cmd, previewCmd, highlightCmd)
+}
+
+// highlighted returns the match under the cursor.
+func (m model) highlighted() string {
+	if m.cursor < 0 || m.cursor >= len(m.matches) {
+		return ""
+	}
+	return m.lines[m.matches[m.cursor].Index]
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		if m.height == 0 || m.height > msg.Height {
-			m.viewport.Height = msg.Height - lipgloss.Height(m.textinput.View())
+			m.viewport.Height = msg.Height - lipgloss.Height(m.textinput.View()) - len(m.header)
 		}
 		m.viewport.Width = msg.Width
+		if m.preview.Enabled() {
+			m.viewport.Width = max(0, msg.Width-m.preview.Width)
+		}
+	case reloadMsg:
+		// The options are left as they were if the command failed.
+		if msg.err != nil {
+			m.err = msg.err.Error()
+			break
+		}
+		m.err = ""
+		cs := m.prepare(msg.lines)
+		m.matcher.setChoices(cs.matched)
+		m.display, m.lines, m.header = cs.display, cs.lines, cs.header
+		m.selected = make(map[int]struct{})
+		m.numSelected = 0
+		m.cursor = 0
+		cmd = m.updateMatches()
+	case matchesMsg:
+		if msg.id != m.matchID {
+			break // outdated
+		}
+		m.matches = msg.matches
 	case tea.KeyMsg:
+		if command, ok := m.reload[msg.String()]; ok {
+			return m, reload(command)
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "esc":
 			m.aborted = true
@@ -119,14 +288,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.quitting = true
 			return m, tea.Quit
 		case "ctrl+n", "ctrl+j", "down":
-			m.cursor = clamp(0, len(m.matches)-1, m.cursor+1)
-			if m.cursor >= m.viewport.YOffset+m.viewport.Height {
-				m.viewport.LineDown(1)
+			// With the reverse layout the matches grow upward, so moving
+			// down moves to the previous match.
+			if m.reverse {
+				m.previousMatch()
+			} else {
+				m.nextMatch()
 			}
 		case "ctrl+p", "ctrl+k", "up":
-			m.cursor = clamp(0, len(m.matches)-1, m.cursor-1)
-			if m.cursor < m.viewport.YOffset {
-				m.viewport.SetYOffset(m.cursor)
+			if m.reverse {
+				m.nextMatch()
+			} else {
+				m.previousMatch()
 			}
 		case "tab":
 			if m.limit == 1 {
@@ -134,46 +307,177 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			// Tab is used to toggle selection of current item in the list
-			if _, ok := m.selected[m.matches[m.cursor].Str]; ok {
-				delete(m.selected, m.matches[m.cursor].Str)
+			if _, ok := m.selected[m.matches[m.cursor].Index]; ok {
+				delete(m.selected, m.matches[m.cursor].Index)
 				m.numSelected--
 			} else if m.numSelected < m.limit {
-				m.selected[m.matches[m.cursor].Str] = struct{}{}
+				m.selected[m.matches[m.cursor].Index] = struct{}{}
 				m.numSelected++
 			}
 
-			// Go down by one line
-			m.cursor = clamp(0, len(m.matches)-1, m.cursor+1)
-			if m.cursor >= m.viewport.YOffset+m.viewport.Height {
-				m.viewport.LineDown(1)
+			// Go to the next match
+			m.nextMatch()
+		case "ctrl+a":
+			// Without a limit, select all of the current matches at once, or
+			// deselect them if they are all selected already. Otherwise
+			// ctrl+a moves the cursor of the filter to the start.
+			if !m.noLimit {
+				m.textinput, cmd = m.textinput.Update(msg)
+				break
 			}
+			m.toggleAll()
 		default:
+			value := m.textinput.Value()
 			m.textinput, cmd = m.textinput.Update(msg)
 
 			// A character was entered, this likely means that the text input
 			// has changed. This suggests that the matches are outdated, so
-			// update them, with a fuzzy finding algorithm provided by
-			// https://github.com/sahilm/fuzzy
-			m.matches = fuzzy.Find(m.textinput.Value(), m.choices)
-
-			// If the search field is empty, let's not display the matches
-			// (none), but rather display all possible choices.
-			if m.textinput.Value() == "" {
-				m.matches = matchAll(m.choices)
+			// update them.
+			if m.textinput.Value() != value {
+				cmd = tea.Batch(cmd, m.updateMatches())
 			}
 		}
 	}
 
 	// It's possible that filtering items have caused fewer matches. So, ensure
-	// that the selected index is within the bounds of the number of matches.
+	// that the selected index is within the bounds of the number of matches,
+	// and in view.
 	m.cursor = clamp(0, len(m.matches)-1, m.cursor)
+	m.offset = clamp(0, max(0, len(m.matches)-m.viewport.Height), m.offset)
+	if m.viewport.Height > 0 {
+		m.offset = clamp(m.cursor-m.viewport.Height+1, m.cursor, m.offset)
+	}
 	return m, cmd
 }
 
+// concurrentMatches is the number of choices from which on they are matched
+// concurrently, in the background, rather than on every change of the filter.
+const concurrentMatches = 50000
+
+type matchesMsg struct {
+	id      int
+	matches []fuzzy.Match
+}
+
+// updateMatches matches the choices against the filter, by default with a
+// fuzzy finding algorithm provided by https://github.com/sahilm/fuzzy
+//
+// Many choices are matched in the background instead, the matches are updated
+// once the returned command is done, unless the filter has changed again.
+func (m *model) updateMatches() tea.Cmd {
+	if m.cancelMatch != nil {
+		m.cancelMatch()
+		m.cancelMatch = nil
+	}
+	m.matchID++
+
+	query := m.textinput.Value()
+
+	// If the search field is empty, let's not display the matches
+	// (none), but rather display all possible choices.
+	if query == "" {
+		m.matches = matchAll(m.matcher.choices)
+		return nil
+	}
+
+	if len(m.matcher.choices) < concurrentMatches {
+		m.matches = m.matcher.find(query)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelMatch = cancel
+	id, mt := m.matchID, m.matcher
+	return func() tea.Msg {
+		matches := mt.findConcurrent(ctx, query)
+		if ctx.Err() != nil {
+			return nil
+		}
+		return matchesMsg{id: id, matches: matches}
+	}
+}
+
+type reloadMsg struct {
+	lines []string
+	err   error
+}
+
+// reload runs the command and replaces the options with the lines it prints.
+func reload(command string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := exec.Command("sh", "-c", command).Output() //nolint:gosec
+		if err != nil {
+			// The command tells best why it failed.
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				if line := firstLine(string(exitErr.Stderr)); line != "" {
+					err = errors.New(line)
+				}
+			}
+			return reloadMsg{err: fmt.Errorf("unable to reload: %w", err)}
+		}
+		var lines []string
+		if s := strings.TrimSpace(string(out)); s != "" {
+			lines = strings.Split(s, "\n")
+		}
+		return reloadMsg{lines: lines}
+	}
+}
+
+// firstLine returns the first line of the text that is not blank.
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// toggleAll selects all of the current matches, or deselects them all if they
+// are all selected.
+func (m *model) toggleAll() {
+	all := true
+	for _, match := range m.matches {
+		if _, ok := m.selected[match.Index]; !ok {
+			all = false
+			break
+		}
+	}
+	for _, match := range m.matches {
+		_, ok := m.selected[match.Index]
+		if all && ok {
+			delete(m.selected, match.Index)
+			m.numSelected--
+		} else if !all && !ok {
+			m.selected[match.Index] = struct{}{}
+			m.numSelected++
+		}
+	}
+}
+
+// nextMatch moves the cursor to the next match, scrolling the viewport to keep
+// it in view.
+func (m *model) nextMatch() {
+	m.cursor = clamp(0, len(m.matches)-1, m.cursor+1)
+	if m.cursor >= m.offset+m.viewport.Height {
+		m.offset++
+	}
+}
+
+// previousMatch moves the cursor to the previous match, scrolling the viewport
+// to keep it in view.
+func (m *model) previousMatch() {
+	m.cursor = clamp(0, len(m.matches)-1, m.cursor-1)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+}
+
 func matchAll(options []string) []fuzzy.Match {
 	var matches = make([]fuzzy.Match, len(options))
 	for i, option := range options {
-		matches[i] = fuzzy.Match{Str: option}
+		matches[i] = fuzzy.Match{Str: option, Index: i}
 	}
 	return matches
 }
@@ -188,3 +492,17 @@ func clamp(min, max, val int) int {
 	}
 	return val
 }
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
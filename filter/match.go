@@ -0,0 +1,298 @@
+package filter
+
+import (
+	"context"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sahilm/fuzzy"
+)
+
+// chunkSize is the number of choices a worker matches against the query at a
+// time.
+const chunkSize = 1000
+
+// matcher matches the choices against the query.
+type matcher struct {
+	// mode is one of fuzzy, exact, prefix or regex.
+	mode string
+	// caseSensitivity is one of smart, sensitive or insensitive. Smart case
+	// is insensitive unless the query contains an upper case letter.
+	caseSensitivity string
+	// sort is either score or input. Only fuzzy matches have a score, the
+	// other modes always keep the order of the input.
+	sort string
+	// choices are matched against the query.
+	choices []string
+	// lower holds the choices in lower case for the exact and prefix modes,
+	// so that they are folded once rather than whenever the query changes.
+	lower []string
+}
+
+// setChoices sets the choices that are matched against the query.
+func (mt *matcher) setChoices(choices []string) {
+	mt.choices = choices
+	mt.lower = nil
+	if mt.mode == "exact" || mt.mode == "prefix" {
+		mt.lower = make([]string, len(choices))
+		for i, choice := range choices {
+			mt.lower[i] = lowerCase(choice)
+		}
+	}
+}
+
+// find returns the choices that match the query.
+func (mt matcher) find(query string) []fuzzy.Match {
+	find := mt.compile(query)
+	if find == nil {
+		return nil
+	}
+	matches := find(0, len(mt.choices))
+	mt.sortMatches(matches)
+	return matches
+}
+
+// findConcurrent returns the choices that match the query like find, but
+// matches them in chunks with a worker per CPU. It returns nil once the
+// context is canceled, i.e. when the query has changed in the meantime.
+func (mt matcher) findConcurrent(ctx context.Context, query string) []fuzzy.Match {
+	find := mt.compile(query)
+	if find == nil {
+		return nil
+	}
+
+	chunks := make([][]fuzzy.Match, (len(mt.choices)+chunkSize-1)/chunkSize)
+	next := int64(-1)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				c := int(atomic.AddInt64(&next, 1))
+				if c >= len(chunks) {
+					return
+				}
+				start := c * chunkSize
+				chunks[c] = find(start, min(start+chunkSize, len(mt.choices)))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	var matches []fuzzy.Match
+	for _, chunk := range chunks {
+		matches = append(matches, chunk...)
+	}
+	mt.sortMatches(matches)
+	return matches
+}
+
+// sortMatches sorts the matches by their score, best first, or by the order
+// of the input. Matches with the same score keep the order of the input.
+func (mt matcher) sortMatches(matches []fuzzy.Match) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if mt.sort == "score" && matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Index < matches[j].Index
+	})
+}
+
+// compile returns a function that matches the query against the choices from
+// start up to (but excluding) end, or nil if the query is not valid.
+func (mt matcher) compile(query string) func(start, end int) []fuzzy.Match {
+	sensitive := mt.caseSensitivity == "sensitive" ||
+		(mt.caseSensitivity == "smart" && strings.IndexFunc(query, unicode.IsUpper) >= 0)
+
+	// The choices in lower case are matched against the query in lower case,
+	// but the indexes of the match must be those in the choice itself.
+	choices := mt.choices
+	if !sensitive && mt.lower != nil {
+		choices = mt.lower
+		query = lowerCase(query)
+	}
+	indexes := func(i, start, end int) []int {
+		_, offsets := fold(mt.choices[i], !sensitive)
+		return runeStarts(offsets, start, end)
+	}
+
+	switch mt.mode {
+	case "exact":
+		return func(start, end int) []fuzzy.Match {
+			return mt.findFunc(start, end, func(i int) []int {
+				j := strings.Index(choices[i], query)
+				if j < 0 {
+					return nil
+				}
+				return indexes(i, j, j+len(query))
+			})
+		}
+	case "prefix":
+		return func(start, end int) []fuzzy.Match {
+			return mt.findFunc(start, end, func(i int) []int {
+				if !strings.HasPrefix(choices[i], query) {
+					return nil
+				}
+				return indexes(i, 0, len(query))
+			})
+		}
+	case "regex":
+		if !sensitive {
+			query = "(?i)" + query
+		}
+		re, err := regexp.Compile(query)
+		if err != nil {
+			// The query is likely incomplete, wait until it is valid.
+			return nil
+		}
+		return func(start, end int) []fuzzy.Match {
+			return mt.findFunc(start, end, func(i int) []int {
+				loc := re.FindStringIndex(mt.choices[i])
+				if loc == nil {
+					return nil
+				}
+				_, offsets := fold(mt.choices[i], false)
+				return runeStarts(offsets, loc[0], loc[1])
+			})
+		}
+	default:
+		return func(start, end int) []fuzzy.Match {
+			matches := fuzzy.Find(query, mt.choices[start:end])
+			for i := range matches {
+				matches[i].Index += start
+			}
+			if !sensitive {
+				return matches
+			}
+
+			// The fuzzy finder always ignores case, so drop the matches
+			// where the case differs.
+			var sensitiveMatches []fuzzy.Match
+			for _, match := range matches {
+				if indexes := subsequenceIndexes(match.Str, query); indexes != nil {
+					match.MatchedIndexes = indexes
+					sensitiveMatches = append(sensitiveMatches, match)
+				}
+			}
+			return sensitiveMatches
+		}
+	}
+}
+
+// findFunc returns the choices from start up to (but excluding) end for which
+// match returns the (non-nil) indexes of the matched characters.
+func (mt matcher) findFunc(start, end int, match func(i int) []int) []fuzzy.Match {
+	var matches []fuzzy.Match
+	for i := start; i < end; i++ {
+		if indexes := match(i); indexes != nil {
+			matches = append(matches, fuzzy.Match{Str: mt.choices[i], Index: i, MatchedIndexes: indexes})
+		}
+	}
+	return matches
+}
+
+// subsequenceIndexes returns the byte indexes of the first occurrence of the
+// runes of pattern, in order, in s, or nil if they do not all occur.
+func subsequenceIndexes(s, pattern string) []int {
+	var indexes []int
+	for i, r := range s {
+		if pattern == "" {
+			break
+		}
+		p, size := utf8.DecodeRuneInString(pattern)
+		if r == p {
+			indexes = append(indexes, i)
+			pattern = pattern[size:]
+		}
+	}
+	if pattern != "" {
+		return nil
+	}
+	return indexes
+}
+
+// fold returns s, in lower case if lower is set, along with the index of the
+// rune in s that each byte of the result comes from. Lower casing may change
+// the number of bytes of a rune, so the indexes of a match in the result have
+// to be mapped back to s.
+func fold(s string, lower bool) (string, []int) {
+	var b strings.Builder
+	offsets := make([]int, 0, len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		n := b.Len()
+		if lower && r != utf8.RuneError {
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteString(s[i : i+size])
+		}
+		for ; n < b.Len(); n++ {
+			offsets = append(offsets, i)
+		}
+		i += size
+	}
+	return b.String(), offsets
+}
+
+// lowerCase returns s in lower case, the same as fold does.
+func lowerCase(s string) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			lower, _ := fold(s, true)
+			return lower
+		}
+	}
+	return strings.ToLower(s)
+}
+
+// runeStarts returns the indexes of the runes that the bytes from start up to
+// (but excluding) end come from, like the fuzzy finder does.
+func runeStarts(offsets []int, start, end int) []int {
+	indexes := make([]int, 0, end-start)
+	for _, offset := range offsets[start:end] {
+		if len(indexes) == 0 || indexes[len(indexes)-1] != offset {
+			indexes = append(indexes, offset)
+		}
+	}
+	return indexes
+}
+
+// fields returns the given (one-based) fields of a line, joined by the
+// delimiter. Without a delimiter the fields are separated by whitespace. The
+// whole line is returned if no fields are given.
+func fields(line, delimiter string, nth []int) string {
+	if len(nth) == 0 {
+		return line
+	}
+
+	var parts []string
+	if delimiter == "" {
+		parts = strings.Fields(line)
+		delimiter = " "
+	} else {
+		parts = strings.Split(line, delimiter)
+	}
+
+	selected := make([]string, 0, len(nth))
+	for _, n := range nth {
+		// Negative fields count from the end of the line.
+		if n < 0 {
+			n = len(parts) + n + 1
+		}
+		if n >= 1 && n <= len(parts) {
+			selected = append(selected, parts[n-1])
+		}
+	}
+	return strings.Join(selected, delimiter)
+}
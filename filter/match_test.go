@@ -0,0 +1,71 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	for _, tt := range []struct {
+		line      string
+		delimiter string
+		nth       []int
+		want      string
+	}{
+		{"a  b c", "", nil, "a  b c"},
+		{"a  b c", "", []int{2}, "b"},
+		{"a  b c", "", []int{3, 1}, "c a"},
+		{"a  b c", "", []int{-1}, "c"},
+		{"a  b c", "", []int{4, -4}, ""},
+		{"a:b::d", ":", []int{2, 4}, "b:d"},
+		{"a:b::d", ":", []int{3}, ""},
+	} {
+		if got := fields(tt.line, tt.delimiter, tt.nth); got != tt.want {
+			t.Errorf("fields(%q, %q, %v) = %q, want %q", tt.line, tt.delimiter, tt.nth, got, tt.want)
+		}
+	}
+}
+
+func TestMatcherFind(t *testing.T) {
+	choices := []string{"Apple pie", "apple", "Ärger", "pineapple"}
+
+	for _, tt := range []struct {
+		mode, caseSensitivity, query string
+		want                         []int
+	}{
+		{"exact", "smart", "apple", []int{0, 1, 3}},
+		{"exact", "smart", "Apple", []int{0}},
+		{"exact", "sensitive", "apple", []int{1, 3}},
+		{"exact", "insensitive", "ärg", []int{2}},
+		{"prefix", "smart", "apple", []int{0, 1}},
+		{"regex", "smart", `^a\w+$`, []int{1}},
+		{"regex", "smart", "(", nil},
+		{"fuzzy", "smart", "ape", []int{0, 1, 3}},
+		{"fuzzy", "smart", "Ape", []int{0}},
+	} {
+		mt := matcher{mode: tt.mode, caseSensitivity: tt.caseSensitivity, sort: "input"}
+		mt.setChoices(choices)
+
+		var got []int
+		for _, match := range mt.find(tt.query) {
+			got = append(got, match.Index)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s %s find(%q) = %v, want %v", tt.mode, tt.caseSensitivity, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestMatcherIndexes(t *testing.T) {
+	// The indexes are those of the runes in the choice, which lower casing
+	// may make longer or shorter.
+	mt := matcher{mode: "exact", caseSensitivity: "insensitive", sort: "input"}
+	mt.setChoices([]string{"ÄÖx"})
+	matches := mt.find("öx")
+	if len(matches) != 1 {
+		t.Fatalf("find() = %v, want one match", matches)
+	}
+	if got, want := matches[0].MatchedIndexes, []int{2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("indexes = %v, want %v", got, want)
+	}
+}
@@ -19,5 +19,25 @@ type Options struct {
 	PromptStyle           style.Styles `embed:"" prefix:"prompt." set:"defaultForeground=240" envprefix:"GUM_FILTER_PROMPT_"`
 	Width                 int          `help:"Input width" default:"20" env:"GUM_FILTER_WIDTH"`
 	Height                int          `help:"Input height" default:"0" env:"GUM_FILTER_HEIGHT"`
+	Match                 string       `help:"How to match the options against the filter" enum:"fuzzy,exact,prefix,regex" default:"fuzzy" env:"GUM_FILTER_MATCH"`
+	Case                  string       `help:"Case sensitivity of the matching (smart ignores case unless the filter has upper case letters)" enum:"smart,sensitive,insensitive" default:"insensitive" env:"GUM_FILTER_CASE"`
+	Sort                  string       `help:"Order of the matches, by score or by their order in the input" enum:"score,input" default:"score" env:"GUM_FILTER_SORT"`
+	Preview               string       `help:"Command to preview the highlighted match with, {} is replaced by the match" default:"" env:"GUM_FILTER_PREVIEW"`
+	PreviewWidth          int          `help:"Width of the preview pane" default:"60" env:"GUM_FILTER_PREVIEW_WIDTH"`
+	PreviewStyle          style.Styles `embed:"" prefix:"preview." set:"defaultMargin=0 0 0 2" envprefix:"GUM_FILTER_PREVIEW_"`
+	ANSI                  bool         `name:"ansi" help:"Keep the ANSI colors of the options, matching on the text without them" default:"false" env:"GUM_FILTER_ANSI"`
+	SelectIfOne           bool         `help:"Select the match right away if there is only one for the initial filter value" default:"false" group:"Selection" env:"GUM_FILTER_SELECT_IF_ONE"`
+	ExitIfEmpty           bool         `help:"Exit with status 2 right away if there are no matches for the initial filter value" default:"false" env:"GUM_FILTER_EXIT_IF_EMPTY"`
+	Delimiter             string       `help:"Delimiter between the fields of an option (defaults to whitespace)" default:"" env:"GUM_FILTER_DELIMITER"`
+	Nth                   []int        `help:"Fields to match the filter against, one-based and negative from the end (defaults to the displayed fields)" default:"" env:"GUM_FILTER_NTH"`
+	WithNth               []int        `help:"Fields to display, one-based and negative from the end (defaults to all)" default:"" env:"GUM_FILTER_WITH_NTH"`
+	Layout                string       `help:"Layout of the filter, reverse puts the input below the matches" enum:"default,reverse" default:"default" env:"GUM_FILTER_LAYOUT"`
+	PrintQuery            bool         `help:"Print the filter value if it matches nothing, exiting with status 2" default:"false" env:"GUM_FILTER_PRINT_QUERY"`
+	Tac                   bool         `help:"Reverse the order of the options" env:"GUM_FILTER_TAC"`
+	HeaderLines           int          `help:"Number of leading lines of the input to display as a header" default:"0" env:"GUM_FILTER_HEADER_LINES"`
+	HeaderStyle           style.Styles `embed:"" prefix:"header." set:"defaultForeground=99" envprefix:"GUM_FILTER_HEADER_"`
+	Bind                  []string     `help:"Key bindings of the form KEY:reload(COMMAND), reloading the options with the output of the command" sep:"none" env:"GUM_FILTER_BIND"`
+	ErrorStyle            style.Styles `embed:"" prefix:"error." set:"defaultForeground=9" envprefix:"GUM_FILTER_ERROR_"`
 	Value                 string       `help:"Initial filter value" default:"" env:"GUM_FILTER_VALUE"`
+	Query                 string       `help:"Initial filter value (same as --value)" default:"" env:"GUM_FILTER_QUERY"`
 }
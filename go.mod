@@ -3,6 +3,7 @@ module github.com/charmbracelet/gum
 go 1.16
 
 require (
+	github.com/alecthomas/chroma v0.10.0
 	github.com/alecthomas/kong v0.6.1
 	github.com/alecthomas/mango-kong v0.1.0
 	github.com/charmbracelet/bubbles v0.14.1-0.20220926062606-e857875f2a75
@@ -10,7 +11,9 @@ require (
 	github.com/charmbracelet/glamour v0.5.1-0.20220727184942-e70ff2d969da
 	github.com/charmbracelet/lipgloss v0.6.0
 	github.com/mattn/go-runewidth v0.0.14
+	github.com/muesli/reflow v0.3.0
 	github.com/muesli/roff v0.1.0
 	github.com/muesli/termenv v0.11.1-0.20220212125758-44cd13922739
+	github.com/rivo/uniseg v0.2.0
 	github.com/sahilm/fuzzy v0.1.0
 )
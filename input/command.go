@@ -3,42 +3,143 @@ package input
 import (
 	"fmt"
 	"os"
+	"os/exec"
+	"regexp"
+	"strings"
 
 	"github.com/alecthomas/kong"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/charmbracelet/gum/internal/ansi"
 	"github.com/charmbracelet/gum/internal/exit"
 	"github.com/charmbracelet/gum/internal/stdin"
 	"github.com/charmbracelet/gum/style"
 )
 
+const (
+	// defaultPlaceholder is the default of --placeholder, which the mask
+	// replaces.
+	defaultPlaceholder = "Type something..."
+	// defaultPrompt is the default of --prompt, which the labels replace.
+	defaultPrompt = "> "
+)
+
 // Run provides a shell script interface for the text input bubble.
 // https://github.com/charmbracelet/bubbles/textinput
 func (o Options) Run() error {
+	if len(o.Fields) > 0 {
+		return o.runFields()
+	}
+
 	i := textinput.New()
 	if in, _ := stdin.Read(); in != "" && o.Value == "" {
-		i.SetValue(in)
+		i.SetValue(strings.TrimSuffix(in, "\n"))
 	} else {
 		i.SetValue(o.Value)
 	}
 
+	// The initial value is there to be edited, so start at its end.
+	i.CursorEnd()
+
 	i.Focus()
 	i.Prompt = o.Prompt
 	i.Placeholder = o.Placeholder
 	i.Width = o.Width
 	i.PromptStyle = o.PromptStyle.ToLipgloss()
 	i.CursorStyle = o.CursorStyle.ToLipgloss()
-	i.CharLimit = o.CharLimit
 
 	if o.Password {
 		i.EchoMode = textinput.EchoPassword
 		i.EchoCharacter = '•'
 	}
 
+	// The mask shows what to type, and how much.
+	var mk mask
+	if o.Mask != "" {
+		mk = mask(o.Mask)
+		if o.Placeholder == defaultPlaceholder {
+			i.Placeholder = o.Mask
+		}
+		i.CharLimit = len(mk)
+		o.CharLimit = 0
+	}
+
+	validate, err := o.validation()
+	if err != nil {
+		return err
+	}
+
+	number, err := newNumber(o.Type, o.Min, o.Max, o.Step)
+	if err != nil {
+		return err
+	}
+
+	var suggestions []string
+	for _, suggestion := range o.Suggestions {
+		if suggestion != "" {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	if o.SuggestionsCmd != "" {
+		out, err := exec.Command("sh", "-c", o.SuggestionsCmd).Output() //nolint:gosec
+		if err != nil {
+			return fmt.Errorf("unable to run suggestions command: %w", err)
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			if line != "" {
+				suggestions = append(suggestions, line)
+			}
+		}
+	}
+
+	var history []string
+	if o.HistoryFile != "" {
+		o.HistoryFile = expandHome(o.HistoryFile)
+		var err error
+		history, err = readHistory(o.HistoryFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.Complete == "none" {
+		o.Complete = ""
+	}
+
 	p := tea.NewProgram(model{
-		textinput: i,
-		aborted:   false,
+		textinput:       i,
+		aborted:         false,
+		validate:        validate,
+		validateMessage: o.ValidateMessage,
+		number:          number,
+		mask:            mk,
+		errorStyle:      o.ErrorStyle.ToLipgloss(),
+
+		suggestions:             suggestions,
+		suggestionStyle:         o.SuggestionStyle.ToLipgloss(),
+		selectedSuggestionStyle: o.SelectedSuggestionStyle.ToLipgloss(),
+		complete:                o.Complete,
+		base:                    expandHome(o.Base),
+
+		history:      history,
+		historyIndex: len(history),
+
+		confirm:            o.Confirm,
+		placeholder:        o.Placeholder,
+		confirmPlaceholder: o.ConfirmPlaceholder,
+
+		reveal: o.Password && o.Reveal,
+
+		showCount:  o.ShowCount,
+		countStyle: o.CountStyle.ToLipgloss(),
+
+		hasTimeout:   o.Timeout > 0,
+		timeout:      o.Timeout,
+		defaultValue: o.Default,
+		timeoutStyle: o.TimeoutStyle.ToLipgloss(),
+
+		charLimit: o.CharLimit,
 	}, tea.WithOutput(os.Stderr))
 	tm, err := p.StartReturningModel()
 	if err != nil {
@@ -46,11 +147,144 @@ func (o Options) Run() error {
 	}
 	m := tm.(model)
 
+	if m.aborted {
+		return exit.ErrAborted
+	}
+	if m.invalid {
+		return fmt.Errorf("invalid value once the timeout ran out: %s", m.err)
+	}
+
+	value := o.normalize(m.textinput.Value())
+	fmt.Println(value)
+
+	// Passwords are not written to the history, where they would be kept in
+	// plain text.
+	if o.HistoryFile != "" && !o.Password {
+		return writeHistory(o.HistoryFile, history, value)
+	}
+	return nil
+}
+
+// validation returns the pattern of --validate, if any, and fills in its
+// message.
+func (o *Options) validation() (*regexp.Regexp, error) {
+	if o.Validate == "" {
+		return nil, nil
+	}
+	validate, err := regexp.Compile(o.Validate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid validation pattern: %w", err)
+	}
+	if o.ValidateMessage == "" {
+		o.ValidateMessage = fmt.Sprintf("Must match %s", o.Validate)
+	}
+	return validate, nil
+}
+
+// normalize applies --strip-ansi, --trim and --lowercase to a value.
+func (o Options) normalize(value string) string {
+	if o.StripANSI {
+		value = ansi.Strip(value)
+	}
+	if o.Trim {
+		value = strings.TrimSpace(value)
+	}
+	if o.Lowercase {
+		value = strings.ToLower(value)
+	}
+	return value
+}
+
+// checkFields fails on the flags that are about a single value, which could
+// not be applied to every field.
+func (o Options) checkFields() error {
+	var suggestions bool
+	for _, suggestion := range o.Suggestions {
+		suggestions = suggestions || suggestion != ""
+	}
+	for _, flag := range []struct {
+		name string
+		set  bool
+	}{
+		{"--value", o.Value != ""},
+		{"--mask", o.Mask != ""},
+		{"--confirm", o.Confirm},
+		{"--type", o.Type != "text"},
+		{"--suggestions", suggestions},
+		{"--suggestions-cmd", o.SuggestionsCmd != ""},
+		{"--complete", o.Complete != "none"},
+		{"--history-file", o.HistoryFile != ""},
+		{"--timeout", o.Timeout > 0},
+		{"--show-count", o.ShowCount},
+	} {
+		if flag.set {
+			return fmt.Errorf("%s can not be used with --fields", flag.name)
+		}
+	}
+	return nil
+}
+
+// runFields asks for the values of several fields on one screen. The
+// placeholder, prompt, limit, validation and normalization apply to every
+// field.
+func (o Options) runFields() error {
+	if err := o.checkFields(); err != nil {
+		return err
+	}
+	validate, err := o.validation()
+	if err != nil {
+		return err
+	}
+
+	m := fieldsModel{
+		labels:          o.Fields,
+		inputs:          make([]textinput.Model, len(o.Fields)),
+		labelStyle:      o.LabelStyle.ToLipgloss(),
+		validate:        validate,
+		validateMessage: o.ValidateMessage,
+		errorStyle:      o.ErrorStyle.ToLipgloss(),
+	}
+	for i := range o.Fields {
+		input := textinput.New()
+		// The labels already show what to type, so the defaults are left out.
+		input.Prompt = ""
+		if o.Prompt != defaultPrompt {
+			input.Prompt = o.Prompt
+			input.PromptStyle = o.PromptStyle.ToLipgloss()
+		}
+		if o.Placeholder != defaultPlaceholder {
+			input.Placeholder = o.Placeholder
+		}
+		input.Width = o.Width
+		input.CursorStyle = o.CursorStyle.ToLipgloss()
+		input.CharLimit = o.CharLimit
+		if o.Password {
+			input.EchoMode = textinput.EchoPassword
+			input.EchoCharacter = '•'
+		}
+		m.inputs[i] = input
+	}
+	m.inputs[0].Focus()
+
+	tm, err := tea.NewProgram(m, tea.WithOutput(os.Stderr)).StartReturningModel()
+	if err != nil {
+		return fmt.Errorf("failed to run input: %w", err)
+	}
+	m = tm.(fieldsModel)
+
 	if m.aborted {
 		return exit.ErrAborted
 	}
 
-	fmt.Println(m.textinput.Value())
+	for i, input := range m.inputs {
+		m.inputs[i].SetValue(o.normalize(input.Value()))
+	}
+
+	out, err := m.format(o.FieldsFormat)
+	if err != nil {
+		return fmt.Errorf("unable to format fields: %w", err)
+	}
+	fmt.Println(out)
 	return nil
 }
 
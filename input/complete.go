@@ -0,0 +1,56 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// completePath returns the paths that complete the value, relative to the base
+// directory unless the value is an absolute path. Only directories complete
+// it if dirsOnly is set. Directories end in a slash, so that completing one
+// moves on to its entries.
+func completePath(base, value string, dirsOnly bool) []string {
+	dir, prefix := "", value
+	if i := strings.LastIndex(value, "/"); i >= 0 {
+		dir, prefix = value[:i+1], value[i+1:]
+	}
+
+	path := expandHome(dir)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		// Hidden files are only completed when asked for.
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".") {
+			continue
+		}
+
+		isDir := entry.IsDir()
+		if entry.Type()&os.ModeSymlink != 0 {
+			if info, err := os.Stat(filepath.Join(path, name)); err == nil {
+				isDir = info.IsDir()
+			}
+		}
+		if isDir {
+			name += "/"
+		} else if dirsOnly {
+			continue
+		}
+
+		if dir+name != value {
+			paths = append(paths, dir+name)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,141 @@
+package input
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fieldsModel asks for several labeled values on one screen, moving between
+// them with tab.
+type fieldsModel struct {
+	labels     []string
+	inputs     []textinput.Model
+	focus      int
+	aborted    bool
+	labelStyle lipgloss.Style
+
+	// validate is the pattern that every field must match to be submitted.
+	validate        *regexp.Regexp
+	validateMessage string
+	err             string
+	errorStyle      lipgloss.Style
+}
+
+func (m fieldsModel) Init() tea.Cmd { return textinput.Blink }
+
+func (m fieldsModel) View() string {
+	var width int
+	for _, label := range m.labels {
+		width = max(width, lipgloss.Width(label))
+	}
+
+	// The labels are lined up so that the inputs start in the same column.
+	lines := make([]string, len(m.inputs))
+	for i, input := range m.inputs {
+		label := m.labels[i] + strings.Repeat(" ", width-lipgloss.Width(m.labels[i]))
+		lines[i] = m.labelStyle.Render(label) + " " + input.View()
+	}
+	if m.err != "" {
+		lines = append(lines, m.errorStyle.Render(m.err))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m fieldsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.aborted = true
+			return m, tea.Quit
+		case "enter":
+			// Enter moves on to the next field, and submits the last one.
+			if m.focus == len(m.inputs)-1 {
+				return m.submit()
+			}
+			return m, m.focusField(m.focus + 1)
+		case "tab", "down":
+			return m, m.focusField((m.focus + 1) % len(m.inputs))
+		case "shift+tab", "up":
+			return m, m.focusField((m.focus + len(m.inputs) - 1) % len(m.inputs))
+		}
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	if m.err != "" && m.invalid() < 0 {
+		m.err = ""
+	}
+	return m, cmd
+}
+
+// submit quits once every field is valid, or moves to the first one that is
+// not.
+func (m fieldsModel) submit() (tea.Model, tea.Cmd) {
+	i := m.invalid()
+	if i < 0 {
+		m.err = ""
+		return m, tea.Quit
+	}
+	m.err = m.labels[i] + ": " + m.validateMessage
+	return m, m.focusField(i)
+}
+
+// invalid returns the index of the first field that does not match the
+// validation pattern, or -1.
+func (m fieldsModel) invalid() int {
+	if m.validate == nil {
+		return -1
+	}
+	for i, input := range m.inputs {
+		if !m.validate.MatchString(input.Value()) {
+			return i
+		}
+	}
+	return -1
+}
+
+// focusField moves the focus to the given field.
+func (m *fieldsModel) focusField(i int) tea.Cmd {
+	m.inputs[m.focus].Blur()
+	m.focus = i
+	return m.inputs[m.focus].Focus()
+}
+
+// format returns the values of the fields separated by tabs, or as a JSON
+// object of the labels to the values.
+func (m fieldsModel) format(format string) (string, error) {
+	if format != "json" {
+		values := make([]string, len(m.inputs))
+		for i, input := range m.inputs {
+			values[i] = input.Value()
+		}
+		return strings.Join(values, "\t"), nil
+	}
+
+	// The fields are written one by one to keep them in order.
+	var b strings.Builder
+	b.WriteRune('{')
+	for i, input := range m.inputs {
+		if i > 0 {
+			b.WriteRune(',')
+		}
+		label, err := json.Marshal(m.labels[i])
+		if err != nil {
+			return "", err //nolint:wrapcheck
+		}
+		value, err := json.Marshal(input.Value())
+		if err != nil {
+			return "", err //nolint:wrapcheck
+		}
+		b.Write(label)
+		b.WriteRune(':')
+		b.Write(value)
+	}
+	b.WriteRune('}')
+	return b.String(), nil
+}
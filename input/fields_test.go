@@ -0,0 +1,83 @@
+package input
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newFields(validate string, values ...string) fieldsModel {
+	m := fieldsModel{
+		labels:          make([]string, len(values)),
+		inputs:          make([]textinput.Model, len(values)),
+		validate:        regexp.MustCompile(validate),
+		validateMessage: "Must match " + validate,
+	}
+	for i, value := range values {
+		m.labels[i] = string(rune('a' + i))
+		m.inputs[i] = textinput.New()
+		m.inputs[i].SetValue(value)
+		m.inputs[i].CursorEnd()
+	}
+	m.focus = len(values) - 1
+	m.inputs[m.focus].Focus()
+	return m
+}
+
+// quits reports whether the command quits the program.
+func quits(cmd tea.Cmd) bool {
+	return cmd != nil && cmd() == tea.Quit()
+}
+
+func TestFieldsSubmitFocusesInvalidField(t *testing.T) {
+	m := newFields("^[0-9]+$", "1", "x", "3")
+
+	tm, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = tm.(fieldsModel)
+	if m.focus != 1 {
+		t.Errorf("focus = %d, want 1", m.focus)
+	}
+	if want := "b: Must match ^[0-9]+$"; m.err != want {
+		t.Errorf("err = %q, want %q", m.err, want)
+	}
+	if quits(cmd) {
+		t.Error("submitted an invalid field")
+	}
+
+	// The error goes away once the field is fixed.
+	tm, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	tm, _ = tm.Update(runes("2"))
+	m = tm.(fieldsModel)
+	if m.err != "" {
+		t.Errorf("err = %q, want none", m.err)
+	}
+}
+
+func TestFieldsSubmit(t *testing.T) {
+	m := newFields("^[0-9]+$", "1", "2")
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !quits(cmd) {
+		t.Error("did not submit")
+	}
+}
+
+func TestCheckFields(t *testing.T) {
+	o := Options{Type: "text", Complete: "none", Suggestions: []string{""}}
+	if err := o.checkFields(); err != nil {
+		t.Errorf("checkFields() = %v, want nil", err)
+	}
+	o.Value = "x"
+	if err := o.checkFields(); err == nil {
+		t.Error("checkFields() accepted --value")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	o := Options{Trim: true, Lowercase: true, StripANSI: true}
+	if got, want := o.normalize(" \x1b[1mAbC\x1b[0m "), "abc"; got != want {
+		t.Errorf("normalize() = %q, want %q", got, want)
+	}
+}
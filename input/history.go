@@ -0,0 +1,72 @@
+package input
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxHistory is the number of values kept in the history file.
+const maxHistory = 1000
+
+// readHistory returns the values in the history file, oldest first. A missing
+// file is an empty history.
+func readHistory(path string) ([]string, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read history: %w", err)
+	}
+
+	var history []string
+	for _, line := range strings.Split(string(b), "\n") {
+		if line != "" {
+			history = append(history, line)
+		}
+	}
+	return history, nil
+}
+
+// writeHistory adds the value to the end of the history and writes it to the
+// history file. Earlier occurrences of the value are removed, so that every
+// value is recalled once.
+func writeHistory(path string, history []string, value string) error {
+	if value == "" || strings.Contains(value, "\n") {
+		return nil
+	}
+
+	values := make([]string, 0, len(history)+1)
+	for _, v := range history {
+		if v != value {
+			values = append(values, v)
+		}
+	}
+	values = append(values, value)
+	if len(values) > maxHistory {
+		values = values[len(values)-maxHistory:]
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:gomnd
+		return fmt.Errorf("unable to write history: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(values, "\n")+"\n"), 0o600); err != nil { //nolint:gomnd
+		return fmt.Errorf("unable to write history: %w", err)
+	}
+	return nil
+}
+
+// expandHome replaces a leading ~ in the path with the home directory, for
+// paths that the shell did not expand, such as --history-file=~/history.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
@@ -8,30 +8,445 @@
 package input
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rivo/uniseg"
+
+	"github.com/charmbracelet/gum/internal/timeout"
 )
 
 type model struct {
-	textinput textinput.Model
-	aborted   bool
+	textinput       textinput.Model
+	aborted         bool
+	validate        *regexp.Regexp
+	validateMessage string
+	number          *number
+	mask            mask
+	err             string
+	errorStyle      lipgloss.Style
+
+	suggestions             []string
+	matches                 []string
+	suggestion              int
+	suggestionStyle         lipgloss.Style
+	selectedSuggestionStyle lipgloss.Style
+
+	// complete is files or dirs to suggest paths, relative to the base
+	// directory, instead.
+	complete string
+	base     string
+
+	// history holds the previous values, oldest first. The history index is
+	// len(history) while the value is new, the draft holds the new value
+	// while a previous one is recalled.
+	history      []string
+	historyIndex int
+	draft        string
+
+	// confirm asks for the value twice, first holds the value entered the
+	// first time while it is entered again.
+	confirm            bool
+	confirming         bool
+	first              string
+	placeholder        string
+	confirmPlaceholder string
+
+	// reveal allows to toggle whether a password is masked.
+	reveal bool
+
+	showCount  bool
+	countStyle lipgloss.Style
+
+	// The default value is submitted when the timeout runs out, unless a
+	// key is pressed before. Without a default, the initial value is.
+	hasTimeout   bool
+	timeout      time.Duration
+	defaultValue string
+	timeoutStyle lipgloss.Style
+	// invalid is set if the value submitted once the timeout ran out was
+	// invalid, err holds why.
+	invalid bool
+
+	// charLimit is the maximum number of characters of the value, if any.
+	charLimit int
+}
+
+// maxSuggestions is the number of suggestions displayed below the input.
+const maxSuggestions = 5
+
+func (m model) Init() tea.Cmd {
+	if m.hasTimeout {
+		return tea.Batch(textinput.Blink, timeout.Tick(m.timeout))
+	}
+	return textinput.Blink
+}
+
+func (m model) View() string {
+	var s strings.Builder
+
+	// The countdown precedes the prompt while no key has been pressed.
+	var countdown string
+	if m.hasTimeout {
+		countdown = m.timeoutStyle.Render(fmt.Sprintf("%ds ", max(0, int(m.timeout.Seconds()))))
+	}
+	s.WriteString(countdown)
+	s.WriteString(m.inputView())
+
+	// The number of characters is counted next to the input, out of the
+	// limit if there is one.
+	if m.showCount {
+		count := strconv.Itoa(length(m.textinput.Value()))
+		if limit := m.limit(); limit > 0 {
+			count += "/" + strconv.Itoa(limit)
+		}
+		s.WriteString(" " + m.countStyle.Render(count))
+	}
+
+	// The suggestions that match the value drop down below the input, lined
+	// up with the value.
+	indent := strings.Repeat(" ", lipgloss.Width(countdown+m.textinput.Prompt))
+	for i, match := range m.matches {
+		if i >= maxSuggestions {
+			break
+		}
+		s.WriteString("\n" + indent)
+		if i == m.suggestion {
+			s.WriteString(m.selectedSuggestionStyle.Render(match))
+		} else {
+			s.WriteString(m.suggestionStyle.Render(match))
+		}
+	}
+
+	if m.err != "" {
+		s.WriteString("\n" + m.errorStyle.Render(m.err))
+	}
+	return s.String()
+}
+
+// inputView renders the text input. The placeholder is rendered here, as the
+// text input puts the cursor on its first byte rather than its first
+// character, which garbles placeholders in most scripts other than Latin.
+func (m model) inputView() string {
+	if m.textinput.Value() != "" || m.textinput.Placeholder == "" {
+		return m.textinput.View()
+	}
+
+	// The cursor covers the first character along with the combining marks
+	// that follow it.
+	p := []rune(m.textinput.Placeholder)
+	n := 1
+	for n < len(p) && unicode.In(p[n], unicode.Mn, unicode.Me) {
+		n++
+	}
+
+	c := m.textinput.Cursor
+	c.TextStyle = m.textinput.PlaceholderStyle
+	c.SetChar(string(p[:n]))
+	return m.textinput.PromptStyle.Render(m.textinput.Prompt) +
+		c.View() +
+		m.textinput.PlaceholderStyle.Inline(true).Render(string(p[n:]))
 }
 
-func (m model) Init() tea.Cmd { return textinput.Blink }
-func (m model) View() string  { return m.textinput.View() }
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case timeout.TickMsg:
+		if !m.hasTimeout {
+			return m, nil
+		}
+		m.timeout -= time.Duration(msg)
+		if m.timeout > 0 {
+			return m, timeout.Tick(m.timeout)
+		}
+
+		// Nobody answered in time, so submit the default value, or the
+		// initial value if there is no default, the same way as enter does.
+		// Nobody is there to type it again either, so it is confirmed as it
+		// is, or to correct it, so gum fails if it is invalid.
+		if m.defaultValue != "" {
+			// The text input would cut the default short.
+			if limit := m.limit(); limit > 0 && length(m.defaultValue) > limit {
+				m.err = fmt.Sprintf("Must be at most %d characters", limit)
+				m.invalid = true
+				return m, tea.Quit
+			}
+			m.textinput.SetValue(m.defaultValue)
+		}
+		cmd := m.submit()
+		if m.confirming && m.err == "" {
+			m.textinput.SetValue(m.first)
+			cmd = m.submit()
+		}
+		if m.err != "" {
+			m.invalid = true
+			return m, tea.Quit
+		}
+		return m, cmd
 	case tea.KeyMsg:
+		m.hasTimeout = false
+
 		switch msg.String() {
 		case "ctrl+c", "esc":
 			m.aborted = true
 			return m, tea.Quit
 		case "enter":
-			return m, tea.Quit
+			return m, m.submit()
+		case "ctrl+r":
+			if m.reveal {
+				if m.textinput.EchoMode == textinput.EchoPassword {
+					m.textinput.EchoMode = textinput.EchoNormal
+				} else {
+					m.textinput.EchoMode = textinput.EchoPassword
+				}
+				return m, nil
+			}
+		case "tab":
+			// Paths are suggested once the value changes, or when asked for.
+			if len(m.matches) == 0 && m.complete != "" {
+				m.updateSuggestions()
+				return m, nil
+			}
+
+			// Complete the value with the highlighted suggestion.
+			if len(m.matches) > 0 {
+				m.textinput.SetValue(m.matches[m.suggestion])
+				m.textinput.CursorEnd()
+				m.updateSuggestions()
+			}
+			return m, nil
+		case "up":
+			// Numbers are incremented rather than recalled.
+			if m.number != nil {
+				m.recall(m.number.increment(m.textinput.Value(), 1))
+				return m, nil
+			}
+			if m.historyIndex > 0 {
+				if m.historyIndex == len(m.history) {
+					m.draft = m.textinput.Value()
+				}
+				m.historyIndex--
+				m.recall(m.history[m.historyIndex])
+			}
+			return m, nil
+		case "down":
+			if m.number != nil {
+				m.recall(m.number.increment(m.textinput.Value(), -1))
+				return m, nil
+			}
+			if m.historyIndex < len(m.history) {
+				m.historyIndex++
+				if m.historyIndex == len(m.history) {
+					m.recall(m.draft)
+				} else {
+					m.recall(m.history[m.historyIndex])
+				}
+			}
+			return m, nil
+		case "ctrl+n":
+			if len(m.matches) > 0 {
+				m.suggestion = (m.suggestion + 1) % min(len(m.matches), maxSuggestions)
+				return m, nil
+			}
+		case "ctrl+p":
+			if len(m.matches) > 0 {
+				n := min(len(m.matches), maxSuggestions)
+				m.suggestion = (m.suggestion + n - 1) % n
+				return m, nil
+			}
+		}
+	}
+
+	// Numbers ignore the keystrokes that would not lead to a number.
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.Type == tea.KeyRunes && m.number != nil {
+		value := []rune(m.textinput.Value())
+		pos := m.textinput.Position()
+		typed := string(value[:pos]) + string(msg.Runes) + string(value[pos:])
+		if !m.number.partial(typed) {
+			return m, nil
+		}
+	}
+
+	// Masked values are typed at the cursor, with the literals of the mask
+	// inserted on the way.
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.Type == tea.KeyRunes && m.mask != nil {
+		if value, pos, ok := m.mask.insert(m.textinput.Value(), m.textinput.Position(), msg.Runes); ok {
+			m.textinput.SetValue(value)
+			m.textinput.SetCursor(pos)
+		}
+		if m.err != "" {
+			m.err = m.check()
 		}
+		return m, nil
 	}
 
+	value := m.textinput.Value()
 	var cmd tea.Cmd
-	m.textinput, cmd = m.textinput.Update(msg)
+	m.textinput, cmd = m.updateInput(msg)
+	if m.mask != nil && m.textinput.Value() != value {
+		m.textinput.SetValue(m.mask.trim(m.textinput.Value()))
+	}
+	if m.textinput.Value() != value {
+		m.updateSuggestions()
+	}
+	if m.err != "" {
+		m.err = m.check()
+	}
 	return m, cmd
 }
+
+// updateInput updates the text input, keeping the value within the char limit.
+// The text input counts the characters of its own limit by rune, whereas the
+// char limit counts them as they are displayed: a letter along with its
+// combining marks is one character, however many runes it takes.
+func (m model) updateInput(msg tea.Msg) (textinput.Model, tea.Cmd) {
+	if m.charLimit <= 0 {
+		return m.textinput.Update(msg)
+	}
+
+	// What is typed at once is cut short at the limit.
+	if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyRunes && len(key.Runes) > 1 {
+		cmds := make([]tea.Cmd, 0, len(key.Runes))
+		for _, r := range key.Runes {
+			key.Runes = []rune{r}
+			var cmd tea.Cmd
+			m.textinput, cmd = m.updateInput(key)
+			cmds = append(cmds, cmd)
+		}
+		return m.textinput, tea.Batch(cmds...)
+	}
+
+	// The value is set again rather than kept, as the text input changes it
+	// in place.
+	value, pos := m.textinput.Value(), m.textinput.Position()
+	ti, cmd := m.textinput.Update(msg)
+	if n := length(ti.Value()); n > m.charLimit && n > length(value) {
+		ti.SetValue(value)
+		ti.SetCursor(pos)
+		return ti, nil
+	}
+	return ti, cmd
+}
+
+// limit returns the maximum number of characters of the value, or 0 if there
+// is no limit.
+func (m model) limit() int {
+	if m.charLimit > 0 {
+		return m.charLimit
+	}
+	return m.textinput.CharLimit
+}
+
+// length returns the number of characters of the value, counting a letter
+// along with its combining marks as one, and a wide character as one.
+func length(value string) int {
+	return uniseg.GraphemeClusterCount(value)
+}
+
+// submit quits with the value once it is valid, and confirmed if it has to be.
+// Invalid values can't be submitted, the error stays until the value is valid.
+func (m *model) submit() tea.Cmd {
+	if m.err = m.check(); m.err != "" {
+		return nil
+	}
+	if m.confirm {
+		return m.confirmValue()
+	}
+	return tea.Quit
+}
+
+// confirmValue asks for the value again the first time it is entered, and
+// quits once it has been entered the same way twice. Otherwise it starts over.
+func (m *model) confirmValue() tea.Cmd {
+	value := m.textinput.Value()
+	m.textinput.Reset()
+	m.matches = nil
+
+	if !m.confirming {
+		m.confirming = true
+		m.first = value
+		m.textinput.Placeholder = m.confirmPlaceholder
+		return nil
+	}
+
+	if value == m.first {
+		m.textinput.SetValue(value)
+		return tea.Quit
+	}
+
+	m.confirming = false
+	m.first = ""
+	m.textinput.Placeholder = m.placeholder
+	m.err = "Values do not match, try again"
+	return nil
+}
+
+// recall replaces the value with one from the history.
+func (m *model) recall(value string) {
+	m.textinput.SetValue(value)
+	m.textinput.CursorEnd()
+	m.matches = nil
+	if m.err != "" {
+		m.err = m.check()
+	}
+}
+
+// updateSuggestions finds the suggestions that start with the value, ignoring
+// case, or the paths that complete it, and highlights the first of them.
+func (m *model) updateSuggestions() {
+	m.matches = nil
+	m.suggestion = 0
+
+	if m.complete != "" {
+		m.matches = completePath(m.base, m.textinput.Value(), m.complete == "dirs")
+		return
+	}
+
+	value := strings.ToLower(m.textinput.Value())
+	if value == "" {
+		return
+	}
+	for _, suggestion := range m.suggestions {
+		lower := strings.ToLower(suggestion)
+		if lower != value && strings.HasPrefix(lower, value) {
+			m.matches = append(m.matches, suggestion)
+		}
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// check returns an error message if the value is not a number when it must
+// be, does not fill the mask, or does not match the validation pattern.
+func (m model) check() string {
+	if m.mask != nil && !m.mask.complete(m.textinput.Value()) {
+		return "Must match " + string(m.mask)
+	}
+	if m.number != nil {
+		if err := m.number.check(m.textinput.Value()); err != "" {
+			return err
+		}
+	}
+	if m.validate != nil && !m.validate.MatchString(m.textinput.Value()) {
+		return m.validateMessage
+	}
+	return ""
+}
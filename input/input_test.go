@@ -0,0 +1,98 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newModel returns a model for the value, the way gum input sets it up, with
+// the cursor at the end of the value.
+func newModel(value string) model {
+	i := textinput.New()
+	i.SetValue(value)
+	i.CursorEnd()
+	i.Focus()
+	return model{textinput: i}
+}
+
+// press sends the keys to the model, one after the other.
+func press(m model, keys ...tea.KeyMsg) model {
+	for _, key := range keys {
+		tm, _ := m.Update(key)
+		m = tm.(model)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestLength(t *testing.T) {
+	for value, want := range map[string]int{
+		"":                   0,
+		"abc":                3,
+		"日本語":                3,
+		"e\u0301te":          3,
+		"\u1100\u1161\u11a8": 1,
+	} {
+		if got := length(value); got != want {
+			t.Errorf("length(%q) = %d, want %d", value, got, want)
+		}
+	}
+}
+
+func TestCharLimitCountsCombiningMarks(t *testing.T) {
+	m := newModel("")
+	m.charLimit = 3
+
+	// The combining accent makes up one character with the e before it.
+	m = press(m, runes("e"), runes("\u0301"), runes("t"), runes("e"), runes("s"))
+	if got, want := m.textinput.Value(), "éte"; got != want {
+		t.Errorf("value = %q, want %q", got, want)
+	}
+}
+
+func TestCharLimitCutsWideCharacters(t *testing.T) {
+	m := newModel("")
+	m.charLimit = 2
+
+	m = press(m, runes("日本語"))
+	if got, want := m.textinput.Value(), "日本"; got != want {
+		t.Errorf("value = %q, want %q", got, want)
+	}
+	m = press(m, runes("x"))
+	if got, want := m.textinput.Value(), "日本"; got != want {
+		t.Errorf("value = %q, want %q", got, want)
+	}
+}
+
+func TestCharLimitKeepsCursor(t *testing.T) {
+	m := newModel("日本")
+	m.charLimit = 2
+	m.textinput.SetCursor(1)
+
+	m = press(m, runes("語"))
+	if got, want := m.textinput.Value(), "日本"; got != want {
+		t.Errorf("value = %q, want %q", got, want)
+	}
+	if got, want := m.textinput.Position(), 1; got != want {
+		t.Errorf("position = %d, want %d", got, want)
+	}
+}
+
+func TestMaskTypesAtCursor(t *testing.T) {
+	m := newModel("12-3")
+	m.mask = mask("99-99")
+	m.textinput.SetCursor(1)
+
+	m = press(m, runes("9"))
+	if got, want := m.textinput.Value(), "19-23"; got != want {
+		t.Errorf("value = %q, want %q", got, want)
+	}
+	if got, want := m.textinput.Position(), 2; got != want {
+		t.Errorf("position = %d, want %d", got, want)
+	}
+}
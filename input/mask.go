@@ -0,0 +1,145 @@
+package input
+
+import "unicode"
+
+// mask is a template of the value. Each 9 is a digit, a a letter, * a letter
+// or digit and _ any character, the other characters of the mask are inserted
+// as they are.
+type mask []rune
+
+// literal returns whether the character at i of the mask is inserted as is.
+func (mk mask) literal(i int) bool {
+	switch mk[i] {
+	case '9', 'a', '*', '_':
+		return false
+	}
+	return true
+}
+
+// allows returns whether the character at i of the mask allows r.
+func (mk mask) allows(i int, r rune) bool {
+	switch mk[i] {
+	case '9':
+		return unicode.IsDigit(r)
+	case 'a':
+		return unicode.IsLetter(r)
+	case '*':
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	case '_':
+		return unicode.IsPrint(r)
+	}
+	return r == mk[i]
+}
+
+// insert inserts the typed characters into the value at pos, with the literals
+// of the mask in between the characters, and returns the position after them.
+// The characters after pos move along to the following places of the mask. A
+// literal that is typed moves past it. It returns false if a character is not
+// allowed where it ends up, or the mask is full.
+func (mk mask) insert(value string, pos int, typed []rune) (string, int, bool) {
+	v := []rune(value)
+	for _, r := range typed {
+		i := pos
+		for i < len(mk) && mk.literal(i) && r != mk[i] {
+			i++
+		}
+		if i >= len(mk) {
+			return value, pos, false
+		}
+		if mk.literal(i) {
+			if i >= len(v) {
+				v = append(v, mk[len(v):i+1]...)
+			}
+			pos = i + 1
+			continue
+		}
+
+		// The character goes in between the characters that were typed,
+		// which are laid out through the mask again.
+		var chars []rune
+		var n int
+		for j, c := range v {
+			if mk.literal(j) {
+				continue
+			}
+			if j < i {
+				n++
+			}
+			chars = append(chars, c)
+		}
+		chars = append(chars[:n], append([]rune{r}, chars[n:]...)...)
+		laid, ok := mk.layout(chars)
+		if !ok {
+			return value, pos, false
+		}
+		v = laid
+		pos = mk.place(n) + 1
+	}
+	return string(v), pos, true
+}
+
+// layout returns the characters laid out through the mask, with the literals
+// of the mask in front of them. It returns false if a character is not allowed
+// where it ends up, or there are too many of them.
+func (mk mask) layout(chars []rune) ([]rune, bool) {
+	var v []rune
+	for _, r := range chars {
+		i := mk.place(len(v) - mk.literals(len(v)))
+		if i >= len(mk) || !mk.allows(i, r) {
+			return nil, false
+		}
+		v = append(v, mk[len(v):i]...)
+		v = append(v, r)
+	}
+	return v, true
+}
+
+// place returns the index in the mask of the n-th character that is typed, not
+// counting the literals, or the length of the mask if there is no room for it.
+func (mk mask) place(n int) int {
+	for i := range mk {
+		if mk.literal(i) {
+			continue
+		}
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(mk)
+}
+
+// literals returns the number of literals in the mask before i.
+func (mk mask) literals(i int) int {
+	var n int
+	for j := 0; j < i && j < len(mk); j++ {
+		if mk.literal(j) {
+			n++
+		}
+	}
+	return n
+}
+
+// trim removes the literals from the end of the value, so that deleting a
+// character deletes the literals in front of it too.
+func (mk mask) trim(value string) string {
+	v := []rune(value)
+	for len(v) > 0 && len(v) <= len(mk) && mk.literal(len(v)-1) {
+		v = v[:len(v)-1]
+	}
+	return string(v)
+}
+
+// complete returns whether the value fills the mask.
+func (mk mask) complete(value string) bool {
+	v := []rune(value)
+	if len(v) != len(mk) {
+		return false
+	}
+	for i, r := range v {
+		if !mk.allows(i, r) {
+			return false
+		}
+	}
+	return true
+}
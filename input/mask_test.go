@@ -0,0 +1,56 @@
+package input
+
+import "testing"
+
+func TestMaskInsert(t *testing.T) {
+	tests := []struct {
+		mask, value string
+		pos         int
+		typed       string
+		want        string
+		wantPos     int
+		ok          bool
+	}{
+		{"99-99", "", 0, "1234", "12-34", 5, true},
+		{"99-99", "12-3", 1, "9", "19-23", 2, true},
+		{"99-99", "12-3", 2, "5", "12-53", 4, true},
+		{"99-99", "12", 2, "-", "12-", 3, true},
+		{"99-99", "12-34", 0, "5", "12-34", 0, false},
+		{"99-99", "", 0, "a", "", 0, false},
+		{"9a9", "1b", 0, "2", "1b", 0, false},
+		{"(999) 999", "", 0, "123456", "(123) 456", 9, true},
+	}
+	for _, tt := range tests {
+		got, pos, ok := mask(tt.mask).insert(tt.value, tt.pos, []rune(tt.typed))
+		if got != tt.want || pos != tt.wantPos || ok != tt.ok {
+			t.Errorf("mask %q: insert(%q, %d, %q) = %q, %d, %t, want %q, %d, %t",
+				tt.mask, tt.value, tt.pos, tt.typed, got, pos, ok, tt.want, tt.wantPos, tt.ok)
+		}
+	}
+}
+
+func TestMaskTrim(t *testing.T) {
+	for value, want := range map[string]string{
+		"12-":   "12",
+		"12-3":  "12-3",
+		"":      "",
+		"12-34": "12-34",
+	} {
+		if got := mask("99-99").trim(value); got != want {
+			t.Errorf("trim(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestMaskComplete(t *testing.T) {
+	for value, want := range map[string]bool{
+		"12-34": true,
+		"12-3":  false,
+		"12-3a": false,
+		"12x34": false,
+	} {
+		if got := mask("99-99").complete(value); got != want {
+			t.Errorf("complete(%q) = %t, want %t", value, got, want)
+		}
+	}
+}
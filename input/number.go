@@ -0,0 +1,141 @@
+package input
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// partialInt and partialFloat match the values on the way to a number,
+	// such as a lone minus sign.
+	partialInt   = regexp.MustCompile(`^-?[0-9]*$`)
+	partialFloat = regexp.MustCompile(`^-?[0-9]*\.?[0-9]*$`)
+)
+
+// number restricts the value to integers or floating point numbers within a
+// range.
+type number struct {
+	float bool
+	// min and max are nil when the range is unbounded.
+	min, max *float64
+	step     float64
+}
+
+// newNumber returns the number restrictions for the type of the value, or nil
+// if the value is text.
+func newNumber(typ, min, max string, step float64) (*number, error) {
+	if typ == "text" {
+		return nil, nil //nolint:nilnil
+	}
+
+	n := &number{float: typ == "float", step: step}
+	if !n.float && step != math.Trunc(step) {
+		return nil, fmt.Errorf("step %v is not an integer", step)
+	}
+
+	var err error
+	if n.min, err = n.bound(min); err != nil {
+		return nil, err
+	}
+	if n.max, err = n.bound(max); err != nil {
+		return nil, err
+	}
+	if n.min != nil && n.max != nil && *n.min > *n.max {
+		return nil, fmt.Errorf("minimum %s is greater than maximum %s", min, max)
+	}
+	return n, nil
+}
+
+// bound parses a minimum or maximum, which is nil if it is not set.
+func (n number) bound(s string) (*float64, error) {
+	if s == "" {
+		return nil, nil //nolint:nilnil
+	}
+	v, err := n.parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// parse parses the value as a number of the type.
+func (n number) parse(s string) (float64, error) {
+	if n.float {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", s)
+		}
+		return v, nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an integer", s)
+	}
+	return float64(v), nil
+}
+
+// partial returns whether the value may be typed on the way to a number, so
+// that other keystrokes can be ignored.
+func (n number) partial(s string) bool {
+	if n.float {
+		return partialFloat.MatchString(s)
+	}
+	return partialInt.MatchString(s)
+}
+
+// check returns an error message if the value is not a number in range.
+func (n number) check(s string) string {
+	v, err := n.parse(s)
+	if err != nil {
+		if n.float {
+			return "Must be a number"
+		}
+		return "Must be an integer"
+	}
+	switch {
+	case n.min != nil && n.max != nil && (v < *n.min || v > *n.max):
+		return fmt.Sprintf("Must be between %s and %s", n.format(*n.min, 0), n.format(*n.max, 0))
+	case n.min != nil && v < *n.min:
+		return fmt.Sprintf("Must be at least %s", n.format(*n.min, 0))
+	case n.max != nil && v > *n.max:
+		return fmt.Sprintf("Must be at most %s", n.format(*n.max, 0))
+	}
+	return ""
+}
+
+// increment adds the given number of steps to the value, within the range.
+// Values that are not numbers start from zero, or the nearest bound.
+func (n number) increment(s string, steps int) string {
+	v, _ := n.parse(s)
+	v += float64(steps) * n.step
+	if n.min != nil && v < *n.min {
+		v = *n.min
+	}
+	if n.max != nil && v > *n.max {
+		v = *n.max
+	}
+	return n.format(v, max(decimals(s), decimals(strconv.FormatFloat(n.step, 'f', -1, 64))))
+}
+
+// format formats the number with at least the given number of decimals, for
+// floating point numbers.
+func (n number) format(v float64, prec int) string {
+	if !n.float {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	if prec == 0 {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(v, 'f', prec, 64)
+}
+
+// decimals returns the number of decimals of a number.
+func decimals(s string) int {
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
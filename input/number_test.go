@@ -0,0 +1,93 @@
+package input
+
+import "testing"
+
+func TestNewNumber(t *testing.T) {
+	if n, err := newNumber("text", "", "", 1); n != nil || err != nil {
+		t.Errorf("newNumber(text) = %v, %v, want nil", n, err)
+	}
+	for _, tt := range []struct {
+		typ, min, max string
+		step          float64
+	}{
+		{"int", "", "", 0.5},
+		{"int", "1.5", "", 1},
+		{"float", "x", "", 1},
+		{"int", "10", "1", 1},
+	} {
+		if _, err := newNumber(tt.typ, tt.min, tt.max, tt.step); err == nil {
+			t.Errorf("newNumber(%q, %q, %q, %v) did not fail", tt.typ, tt.min, tt.max, tt.step)
+		}
+	}
+}
+
+func TestNumberPartial(t *testing.T) {
+	integer, _ := newNumber("int", "", "", 1)
+	float, _ := newNumber("float", "", "", 1)
+	for _, tt := range []struct {
+		value      string
+		int, float bool
+	}{
+		{"", true, true},
+		{"-", true, true},
+		{"-12", true, true},
+		{"1.", false, true},
+		{".5", false, true},
+		{"1.2.3", false, false},
+		{"1e3", false, false},
+		{"12-", false, false},
+	} {
+		if got := integer.partial(tt.value); got != tt.int {
+			t.Errorf("int partial(%q) = %v, want %v", tt.value, got, tt.int)
+		}
+		if got := float.partial(tt.value); got != tt.float {
+			t.Errorf("float partial(%q) = %v, want %v", tt.value, got, tt.float)
+		}
+	}
+}
+
+func TestNumberCheck(t *testing.T) {
+	between, _ := newNumber("int", "1", "10", 1)
+	atLeast, _ := newNumber("float", "0.5", "", 1)
+	for _, tt := range []struct {
+		n     *number
+		value string
+		want  string
+	}{
+		{between, "5", ""},
+		{between, "-", "Must be an integer"},
+		{between, "1.5", "Must be an integer"},
+		{between, "11", "Must be between 1 and 10"},
+		{atLeast, "0.25", "Must be at least 0.5"},
+		{atLeast, "x", "Must be a number"},
+		{atLeast, "2.5", ""},
+	} {
+		if got := tt.n.check(tt.value); got != tt.want {
+			t.Errorf("check(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNumberIncrement(t *testing.T) {
+	integer, _ := newNumber("int", "0", "10", 2)
+	float, _ := newNumber("float", "", "", 0.1)
+	for _, tt := range []struct {
+		n     *number
+		value string
+		steps int
+		want  string
+	}{
+		{integer, "4", 1, "6"},
+		{integer, "4", -1, "2"},
+		{integer, "9", 1, "10"},
+		{integer, "1", -1, "0"},
+		{integer, "", 1, "2"},
+		{float, "1.25", 1, "1.35"},
+		{float, "1", -1, "0.9"},
+		{float, "-", 1, "0.1"},
+	} {
+		if got := tt.n.increment(tt.value, tt.steps); got != tt.want {
+			t.Errorf("increment(%q, %d) = %q, want %q", tt.value, tt.steps, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package input
 
-import "github.com/charmbracelet/gum/style"
+import (
+	"time"
+
+	"github.com/charmbracelet/gum/style"
+)
 
 // Options are the customization options for the input.
 type Options struct {
@@ -8,8 +12,46 @@ type Options struct {
 	Prompt      string       `help:"Prompt to display" default:"> " env:"GUM_INPUT_PROMPT"`
 	PromptStyle style.Styles `embed:"" prefix:"prompt." envprefix:"GUM_INPUT_PROMPT_"`
 	CursorStyle style.Styles `embed:"" prefix:"cursor." set:"defaultForeground=212" envprefix:"GUM_INPUT_CURSOR_"`
-	Value       string       `help:"Initial value (can also be passed via stdin)" default:""`
-	CharLimit   int          `help:"Maximum value length (0 for no limit)" default:"400"`
+	Value       string       `help:"Initial value that can be edited, unlike the placeholder (can also be passed via stdin)" default:"" env:"GUM_INPUT_VALUE"`
+	CharLimit   int          `help:"Maximum value length, in characters as they are displayed (0 for no limit)" default:"400"`
+	ShowCount   bool         `help:"Display the number of characters, out of the limit" default:"false" env:"GUM_INPUT_SHOW_COUNT"`
+	CountStyle  style.Styles `embed:"" prefix:"count." set:"defaultForeground=240" envprefix:"GUM_INPUT_COUNT_"`
 	Width       int          `help:"Input width" default:"40" env:"GUM_INPUT_WIDTH"`
 	Password    bool         `help:"Mask input characters" default:"false"`
+	Reveal      bool         `help:"Allow to reveal the masked characters with ctrl+r" default:"true" negatable:"" env:"GUM_INPUT_REVEAL"`
+
+	Confirm            bool   `help:"Ask for the value twice, until both match" default:"false" env:"GUM_INPUT_CONFIRM"`
+	ConfirmPlaceholder string `help:"Placeholder value when asking for the value again" default:"Type it again..." env:"GUM_INPUT_CONFIRM_PLACEHOLDER"`
+
+	Suggestions             []string     `help:"Values to suggest while typing, picked with ctrl+n/ctrl+p and completed with tab" default:"" env:"GUM_INPUT_SUGGESTIONS"`
+	SuggestionsCmd          string       `help:"Command whose output lines are suggested while typing" default:"" env:"GUM_INPUT_SUGGESTIONS_CMD"`
+	Complete                string       `help:"Suggest paths of files or only directories, completed with tab" enum:"none,files,dirs" default:"none" env:"GUM_INPUT_COMPLETE"`
+	Base                    string       `help:"Directory that completed paths are relative to" default:"." env:"GUM_INPUT_BASE"`
+	SuggestionStyle         style.Styles `embed:"" prefix:"suggestion." set:"defaultForeground=240" envprefix:"GUM_INPUT_SUGGESTION_"`
+	SelectedSuggestionStyle style.Styles `embed:"" prefix:"selected-suggestion." set:"defaultForeground=212" envprefix:"GUM_INPUT_SELECTED_SUGGESTION_"`
+
+	HistoryFile string `help:"File to keep previous values in, recalled with up and down (passwords are not kept)" default:"" env:"GUM_INPUT_HISTORY_FILE"`
+
+	Type string  `help:"Type of the value, numbers can be incremented with up and down" enum:"text,int,float" default:"text" env:"GUM_INPUT_TYPE"`
+	Min  string  `help:"Minimum value of a number" default:"" env:"GUM_INPUT_MIN"`
+	Max  string  `help:"Maximum value of a number" default:"" env:"GUM_INPUT_MAX"`
+	Step float64 `help:"Amount by which up and down change a number" default:"1" env:"GUM_INPUT_STEP"`
+
+	Timeout      time.Duration `help:"Timeout after which the default value is submitted, unless a key is pressed" default:"0" env:"GUM_INPUT_TIMEOUT"`
+	Default      string        `help:"Value to submit when the timeout runs out, in place of the initial value (which is submitted without a default)" default:"" env:"GUM_INPUT_DEFAULT"`
+	TimeoutStyle style.Styles  `embed:"" prefix:"timeout." set:"defaultForeground=240" envprefix:"GUM_INPUT_TIMEOUT_"`
+
+	Trim      bool `help:"Remove leading and trailing white space from the value" default:"false" env:"GUM_INPUT_TRIM"`
+	Lowercase bool `help:"Convert the value to lower case" default:"false" env:"GUM_INPUT_LOWERCASE"`
+	StripANSI bool `name:"strip-ansi" help:"Remove ANSI escape sequences from the value" default:"false" env:"GUM_INPUT_STRIP_ANSI"`
+
+	Fields       []string     `help:"Labels of several values to ask for on one screen, moving between them with tab" default:"" env:"GUM_INPUT_FIELDS"`
+	FieldsFormat string       `help:"Format of the values of the fields" enum:"tsv,json" default:"tsv" env:"GUM_INPUT_FIELDS_FORMAT"`
+	LabelStyle   style.Styles `embed:"" prefix:"label." set:"defaultForeground=212" envprefix:"GUM_INPUT_LABEL_"`
+
+	Mask string `help:"Template of the value, where 9 is a digit, a a letter, * a letter or digit and _ any character (other characters are inserted), shown as the placeholder unless there is one" default:"" env:"GUM_INPUT_MASK"`
+
+	Validate        string       `help:"Regular expression that the value must match to be submitted" default:"" env:"GUM_INPUT_VALIDATE"`
+	ValidateMessage string       `help:"Error message displayed when the value does not match --validate" default:"" env:"GUM_INPUT_VALIDATE_MESSAGE"`
+	ErrorStyle      style.Styles `embed:"" prefix:"error." set:"defaultForeground=9" envprefix:"GUM_INPUT_ERROR_"`
 }
@@ -0,0 +1,12 @@
+package ansi
+
+import "regexp"
+
+// sequence matches ANSI escape sequences: CSI sequences (colors, cursor
+// movement) and OSC sequences (titles, hyperlinks).
+var sequence = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// Strip removes all ANSI escape sequences from a string.
+func Strip(s string) string {
+	return sequence.ReplaceAllString(s, "")
+}
@@ -7,3 +7,11 @@ const StatusAborted = 130
 
 // ErrAborted is the error to return when a gum command is aborted by Ctrl + C.
 var ErrAborted = fmt.Errorf("aborted")
+
+// StatusNoMatch is the exit code when nothing matched, which scripts can tell
+// apart from gum failing.
+const StatusNoMatch = 2
+
+// ErrNoMatch is the error to return when a gum command exits because nothing
+// matched.
+var ErrNoMatch = fmt.Errorf("no match")
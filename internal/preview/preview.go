@@ -0,0 +1,132 @@
+// Package preview runs a command for the highlighted item of a list and
+// displays the command's output next to the list.
+package preview
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+// debounce is how long an item has to stay highlighted before its preview is
+// run, so that scrolling through a list doesn't start a command per item.
+const debounce = 150 * time.Millisecond
+
+// placeholder is replaced by the (quoted) item in the preview command.
+const placeholder = "{}"
+
+// Model holds the output of the preview command for the highlighted item.
+type Model struct {
+	Width  int
+	Height int
+	Style  lipgloss.Style
+
+	command string
+	item    string
+	id      int
+	output  string
+	cancel  context.CancelFunc
+}
+
+type debounceMsg struct{ id int }
+
+type outputMsg struct {
+	id     int
+	output string
+}
+
+// New returns a preview model running the given command, which starts out
+// previewing the given item.
+func New(command, item string) Model {
+	return Model{command: command, item: item}
+}
+
+// Enabled reports whether there is a preview command to run.
+func (m Model) Enabled() bool { return m.command != "" }
+
+// Init runs the preview command for the initial item.
+func (m Model) Init() tea.Cmd {
+	if !m.Enabled() {
+		return nil
+	}
+	return run(context.Background(), m.id, m.command, m.item)
+}
+
+// SetItem changes the highlighted item. The preview command is run once the
+// item has stayed highlighted for a moment.
+func (m *Model) SetItem(item string) tea.Cmd {
+	if !m.Enabled() || item == m.item {
+		return nil
+	}
+	m.item = item
+	m.id++
+	id := m.id
+	return tea.Tick(debounce, func(time.Time) tea.Msg {
+		return debounceMsg{id: id}
+	})
+}
+
+// Update runs the preview command once the highlighted item has settled and
+// stores its output. Commands for items that are no longer highlighted are
+// cancelled and their output is ignored.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case debounceMsg:
+		if msg.id != m.id {
+			return m, nil
+		}
+		if m.cancel != nil {
+			m.cancel()
+		}
+		var ctx context.Context
+		ctx, m.cancel = context.WithCancel(context.Background())
+		return m, run(ctx, m.id, m.command, m.item)
+	case outputMsg:
+		if msg.id == m.id {
+			m.output = msg.output
+		}
+	}
+	return m, nil
+}
+
+// View renders the output of the preview command, cut to the size of the
+// preview pane.
+func (m Model) View() string {
+	if !m.Enabled() {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimRight(m.output, "\n"), "\n")
+	if m.Height > 0 && len(lines) > m.Height {
+		lines = lines[:m.Height]
+	}
+	for i, line := range lines {
+		line = strings.ReplaceAll(strings.TrimRight(line, "\r"), "\t", "    ")
+		if m.Width > 0 {
+			line = truncate.String(line, uint(m.Width))
+		}
+		lines[i] = line
+	}
+	return m.Style.Render(strings.Join(lines, "\n"))
+}
+
+func run(ctx context.Context, id int, command, item string) tea.Cmd {
+	return func() tea.Msg {
+		command := strings.ReplaceAll(command, placeholder, quote(item))
+		out, err := exec.CommandContext(ctx, "sh", "-c", command).CombinedOutput() //nolint:gosec
+		if err != nil && len(out) == 0 {
+			out = []byte(err.Error())
+		}
+		return outputMsg{id: id, output: string(out)}
+	}
+}
+
+// quote quotes a string for use as a single argument in a shell command.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,26 @@
+// Package timeout provides the countdown of the commands that stop waiting for
+// the user once their timeout runs out.
+package timeout
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tickInterval is the time between the ticks of the countdown.
+const tickInterval = time.Second
+
+// TickMsg holds the time waited since the last tick.
+type TickMsg time.Duration
+
+// Tick waits for the next second of the countdown, or for what is left of it.
+func Tick(left time.Duration) tea.Cmd {
+	d := tickInterval
+	if left < d {
+		d = left
+	}
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return TickMsg(d)
+	})
+}
@@ -64,6 +64,9 @@ func main() {
 		if errors.Is(err, exit.ErrAborted) {
 			os.Exit(exit.StatusAborted)
 		}
+		if errors.Is(err, exit.ErrNoMatch) {
+			os.Exit(exit.StatusNoMatch)
+		}
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -1,8 +1,11 @@
 package spin
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"regexp"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -15,13 +18,56 @@ import (
 // Run provides a shell script interface for the spinner bubble.
 // https://github.com/charmbracelet/bubbles/spinner
 func (o Options) Run() error {
+	sp, err := o.spinner()
+	if err != nil {
+		return err
+	}
 	s := spinner.New()
 	s.Style = o.SpinnerStyle.ToLipgloss()
-	s.Spinner = spinnerMap[o.Spinner]
+	s.Spinner = sp
+
+	if len(o.Tasks) > 0 {
+		return o.runTasks(s)
+	}
+	if len(o.Command) == 0 {
+		return errors.New("no command to run, see `gum spin --help`")
+	}
+	now := time.Now()
 	m := model{
 		spinner: s,
 		title:   o.TitleStyle.ToLipgloss().Render(o.Title),
 		command: o.Command,
+		timeout: o.Timeout,
+
+		tail:        tail{size: o.ShowOutputLines},
+		outputStyle: o.OutputStyle.ToLipgloss(),
+
+		percent:        -1,
+		started:        now,
+		attemptStarted: now,
+		spinnerStyle:   o.SpinnerStyle.ToLipgloss(),
+
+		showElapsed:  o.ShowElapsed,
+		elapsedStyle: o.ElapsedStyle.ToLipgloss(),
+		keep:         o.Keep,
+
+		retries:    o.Retries,
+		retryDelay: o.RetryDelay,
+		attempt:    1,
+	}
+
+	if o.ProgressRegex != "" || o.Progress {
+		if o.ProgressRegex == "" {
+			o.ProgressRegex = defaultProgress
+		}
+		var err error
+		m.progress, err = regexp.Compile(o.ProgressRegex)
+		if err != nil {
+			return fmt.Errorf("invalid progress pattern: %w", err)
+		}
+	}
+	if o.ShowOutputLines > 0 || m.progress != nil {
+		m.output = make(chan outputMsg)
 	}
 	p := tea.NewProgram(m, tea.WithOutput(os.Stderr))
 	mm, err := p.StartReturningModel()
@@ -31,19 +77,94 @@ func (o Options) Run() error {
 		return fmt.Errorf("failed to run spin: %w", err)
 	}
 
-	if o.ShowOutput {
-		fmt.Fprint(os.Stdout, m.stdout)
-		fmt.Fprint(os.Stderr, m.stderr)
+	if m.aborted {
+		o.showOutput(m.stdout, m.stderr, m.status)
+		return exit.ErrAborted
+	}
+	if o.JSON {
+		if err := printJSON(newResult(m.status, m.elapsed, m.stdout, m.stderr)); err != nil {
+			return err
+		}
+	} else {
+		o.showOutput(m.stdout, m.stderr, m.status)
+	}
+	notify(o.Notify, doneMessage(o.Title, m.status))
+
+	os.Exit(m.status)
+	return nil
+}
+
+// runTasks runs the tasks at the same time. The exit status is that of the
+// first task that failed, if any.
+func (o Options) runTasks(s spinner.Model) error {
+	// The output of the tasks is not shown as they run.
+	switch {
+	case o.ShowOutputLines > 0:
+		return errors.New("--show-output-lines can not be used with --task")
+	case o.Progress || o.ProgressRegex != "":
+		return errors.New("--progress can not be used with --task")
+	}
+
+	m := tasksModel{
+		spinner:    s,
+		titleStyle: o.TitleStyle.ToLipgloss(),
+		timeout:    o.Timeout,
+		retries:    o.Retries,
+		retryDelay: o.RetryDelay,
+
+		keep:         o.Keep,
+		showElapsed:  o.ShowElapsed,
+		elapsedStyle: o.ElapsedStyle.ToLipgloss(),
+	}
+	for _, t := range o.Tasks {
+		task := parseTask(t)
+		task.started = time.Now()
+		m.tasks = append(m.tasks, task)
+	}
+
+	tm, err := tea.NewProgram(m, tea.WithOutput(os.Stderr)).StartReturningModel()
+	if err != nil {
+		return fmt.Errorf("failed to run spin: %w", err)
+	}
+	m = tm.(tasksModel)
+
+	if o.JSON && !m.aborted {
+		results := make([]result, 0, len(m.tasks))
+		for _, t := range m.tasks {
+			r := newResult(t.status, t.elapsed, t.stdout, t.stderr)
+			r.Title = t.title
+			results = append(results, r)
+		}
+		if err := printJSON(results); err != nil {
+			return err
+		}
+	} else {
+		for _, t := range m.tasks {
+			o.showOutput(t.stdout, t.stderr, t.status)
+		}
 	}
 
 	if m.aborted {
 		return exit.ErrAborted
 	}
+	notify(o.Notify, doneMessage("Tasks", m.status()))
 
-	os.Exit(m.status)
+	os.Exit(m.status())
 	return nil
 }
 
+// showOutput prints the output of a command that is asked for. The stderr of
+// a command that failed is always printed, so that the script does not fail
+// silently.
+func (o Options) showOutput(stdout, stderr string, status int) {
+	if o.ShowOutput || o.ShowStdout {
+		fmt.Fprint(os.Stdout, stdout)
+	}
+	if o.ShowOutput || o.ShowStderr || status != 0 {
+		fmt.Fprint(os.Stderr, stderr)
+	}
+}
+
 // BeforeReset hook. Used to unclutter style flags.
 func (o Options) BeforeReset(ctx *kong.Context) error {
 	style.HideFlags(ctx)
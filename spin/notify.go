@@ -0,0 +1,38 @@
+package spin
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/gum/internal/ansi"
+)
+
+// notify tells the user that the command is done, by ringing the bell of the
+// terminal or with a desktop notification through OSC 9, which terminals such
+// as iTerm2, kitty and Windows Terminal support.
+func notify(method, message string) {
+	switch method {
+	case "bell":
+		fmt.Fprint(os.Stderr, "\a")
+	case "osc9":
+		// Control characters would end the sequence early.
+		message = strings.Map(func(r rune) rune {
+			if unicode.IsControl(r) {
+				return -1
+			}
+			return r
+		}, ansi.Strip(message))
+		fmt.Fprintf(os.Stderr, "\x1b]9;%s\a", message)
+	}
+}
+
+// doneMessage returns the message of the notification that the command is
+// done, with whether it succeeded.
+func doneMessage(title string, status int) string {
+	if status != 0 {
+		return fmt.Sprintf("%s failed with exit status %d", title, status)
+	}
+	return title + " succeeded"
+}
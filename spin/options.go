@@ -1,14 +1,36 @@
 package spin
 
-import "github.com/charmbracelet/gum/style"
+import (
+	"time"
+
+	"github.com/charmbracelet/gum/style"
+)
 
 // Options is the customization options for the spin command.
 type Options struct {
-	Command []string `arg:"" help:"Command to run"`
+	Command []string `arg:"" optional:"" help:"Command to run"`
+	Tasks   []string `name:"task" help:"Task to run along with the others, as TITLE::COMMAND (instead of a command)" sep:"none" env:"GUM_SPIN_TASK"`
 
-	ShowOutput   bool         `help:"Show output of command" default:"false" env:"GUM_SPIN_SHOW_OUTPUT"`
-	Spinner      string       `help:"Spinner type" short:"s" type:"spinner" enum:"line,dot,minidot,jump,pulse,points,globe,moon,monkey,meter,hamburger" default:"dot" env:"GUM_SPIN_SPINNER"`
-	SpinnerStyle style.Styles `embed:"" prefix:"spinner." set:"defaultForeground=212" envprefix:"GUM_SPIN_SPINNER_"`
-	Title        string       `help:"Text to display to user while spinning" default:"Loading..." env:"GUM_SPIN_TITLE"`
-	TitleStyle   style.Styles `embed:"" prefix:"title." envprefix:"GUM_SPIN_TITLE_"`
+	ShowOutput      bool          `help:"Show output of command" default:"false" env:"GUM_SPIN_SHOW_OUTPUT"`
+	ShowStdout      bool          `help:"Show the stdout of the command, the stderr is shown when it fails" default:"false" env:"GUM_SPIN_SHOW_STDOUT"`
+	ShowStderr      bool          `help:"Show the stderr of the command, even when it succeeds" default:"false" env:"GUM_SPIN_SHOW_STDERR"`
+	ShowOutputLines int           `help:"Number of the last lines of output to show below the spinner while the command runs" default:"0" env:"GUM_SPIN_SHOW_OUTPUT_LINES"`
+	OutputStyle     style.Styles  `embed:"" prefix:"output." set:"defaultForeground=240" envprefix:"GUM_SPIN_OUTPUT_"`
+	Progress        bool          `help:"Show a progress bar instead of the spinner, filled up to the last percentage in the output" default:"false" env:"GUM_SPIN_PROGRESS"`
+	ProgressRegex   string        `help:"Pattern of the percentages in the output, with the number in its first group (implies --progress)" default:"" env:"GUM_SPIN_PROGRESS_REGEX"`
+	Spinner         string        `help:"Spinner type (line, dot, minidot, jump, pulse, points, globe, moon, monkey, meter, hamburger, or one of --spinners)" short:"s" type:"spinner" default:"dot" env:"GUM_SPIN_SPINNER"`
+	Spinners        string        `help:"JSON file of custom spinners by their names, with their frames and interval" default:"" env:"GUM_SPIN_SPINNERS"`
+	SpinnerFrames   []string      `help:"Frames of the spinner, in place of its own" env:"GUM_SPIN_SPINNER_FRAMES"`
+	SpinnerInterval time.Duration `help:"Time between the frames of the spinner, in place of its own" default:"0" env:"GUM_SPIN_SPINNER_INTERVAL"`
+	SpinnerStyle    style.Styles  `embed:"" prefix:"spinner." set:"defaultForeground=212" envprefix:"GUM_SPIN_SPINNER_"`
+	Title           string        `help:"Text to display to user while spinning" default:"Loading..." env:"GUM_SPIN_TITLE"`
+	TitleStyle      style.Styles  `embed:"" prefix:"title." envprefix:"GUM_SPIN_TITLE_"`
+	ShowElapsed     bool          `help:"Show the time elapsed next to the title" default:"false" env:"GUM_SPIN_SHOW_ELAPSED"`
+	ElapsedStyle    style.Styles  `embed:"" prefix:"elapsed." set:"defaultForeground=240" envprefix:"GUM_SPIN_ELAPSED_"`
+	Keep            bool          `help:"Keep whether the command (or each task) succeeded, and how long it took, on screen once it is done" default:"false" env:"GUM_SPIN_KEEP"`
+	Timeout         time.Duration `help:"Timeout after which the command is terminated, and gum exits with status 124" default:"0" env:"GUM_SPIN_TIMEOUT"`
+	Retries         int           `help:"Number of times to run the command again when it fails" default:"0" env:"GUM_SPIN_RETRIES"`
+	RetryDelay      time.Duration `help:"Time to wait before the command is run again" default:"1s" env:"GUM_SPIN_RETRY_DELAY"`
+	JSON            bool          `help:"Print the exit code, duration, stdout and stderr of the command as JSON once it is done, in place of its output (an array of them for tasks)" name:"json" default:"false" env:"GUM_SPIN_JSON"`
+	Notify          string        `help:"Notify when the command is done: ring the bell, or send a desktop notification through the terminal with osc9" enum:"none,bell,osc9" default:"none" env:"GUM_SPIN_NOTIFY"`
 }
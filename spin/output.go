@@ -0,0 +1,127 @@
+package spin
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/charmbracelet/gum/internal/ansi"
+)
+
+// outputMsg is a chunk of the output of the command, as it runs.
+type outputMsg []byte
+
+// outputWriter sends what the command writes to the model.
+type outputWriter chan<- outputMsg
+
+func (w outputWriter) Write(p []byte) (int, error) {
+	w <- outputMsg(append([]byte(nil), p...))
+	return len(p), nil
+}
+
+// waitForOutput waits for the next chunk of output of the command.
+func waitForOutput(output <-chan outputMsg) tea.Cmd {
+	return func() tea.Msg {
+		return <-output
+	}
+}
+
+// tail holds the last lines of the output of the command, and the line that
+// is being written, which a carriage return starts over, as progress bars do.
+type tail struct {
+	size  int
+	lines []string
+	line  []byte
+	cr    bool
+}
+
+func (t *tail) write(p []byte) {
+	for _, b := range p {
+		switch {
+		case b == '\n':
+			t.lines = append(t.lines, string(t.line))
+			if len(t.lines) > t.size {
+				t.lines = t.lines[len(t.lines)-t.size:]
+			}
+			t.line = t.line[:0]
+			t.cr = false
+		case b == '\r':
+			t.cr = true
+		default:
+			if t.cr {
+				t.line = t.line[:0]
+				t.cr = false
+			}
+			t.line = append(t.line, b)
+		}
+	}
+}
+
+// view returns the last lines of the output, without their colors and with
+// their tabs expanded, so that they can be cut to the width of the terminal.
+func (t tail) view() []string {
+	lines := t.lines
+	if len(t.line) > 0 {
+		lines = append(lines[:len(lines):len(lines)], string(t.line))
+	}
+	if len(lines) > t.size {
+		lines = lines[len(lines)-t.size:]
+	}
+
+	view := make([]string, len(lines))
+	for i, line := range lines {
+		view[i] = strings.ReplaceAll(ansi.Strip(line), "\t", "    ")
+	}
+	return view
+}
+
+// defaultProgress matches the percentages in the output, such as 42% or 42.5%.
+const defaultProgress = `(\d+(?:\.\d+)?)%`
+
+// progressWidth is the width of the progress bar.
+const progressWidth = 30
+
+// carry is the number of bytes at the end of a chunk of output that are kept
+// to be matched along with the next chunk, for the percentages that are
+// written in two.
+const carry = 32
+
+// scanProgress returns the last percentage in the output that the pattern
+// matches, either in its first group or as a whole, or -1 if there is none.
+func scanProgress(pattern *regexp.Regexp, output string) float64 {
+	matches := pattern.FindAllStringSubmatch(output, -1)
+	for i := len(matches) - 1; i >= 0; i-- {
+		match := matches[i][0]
+		if len(matches[i]) > 1 {
+			match = matches[i][1]
+		}
+		if percent, err := strconv.ParseFloat(strings.TrimSuffix(match, "%"), 64); err == nil {
+			return math.Min(math.Max(percent, 0), 100) //nolint:gomnd
+		}
+	}
+	return -1
+}
+
+// progressView renders a bar that is filled up to the percentage, followed by
+// the percentage and the time that is left. The time left is estimated from
+// the time elapsed until the percentage was reached, and counts down while
+// the percentage stays the same.
+func progressView(percent float64, elapsed, since time.Duration) string {
+	filled := int(percent / 100 * progressWidth) //nolint:gomnd
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressWidth-filled)
+
+	s := fmt.Sprintf("%s %3.0f%%", bar, percent)
+	if percent > 0 && percent < 100 {
+		eta := time.Duration(float64(elapsed)*(100-percent)/percent) - since //nolint:gomnd
+		if eta < 0 {
+			eta = 0
+		}
+		s += " ETA " + eta.Round(time.Second).String()
+	}
+	return s
+}
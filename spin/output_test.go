@@ -0,0 +1,63 @@
+package spin
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTail(t *testing.T) {
+	tl := tail{size: 2}
+	tl.write([]byte("one\ntwo\nthr"))
+	tl.write([]byte("ee\n10%\r20%"))
+	if got, want := tl.view(), []string{"three", "20%"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("view() = %q, want %q", got, want)
+	}
+
+	tl.write([]byte("\n\x1b[1mbold\x1b[0m\tdone"))
+	if got, want := tl.view(), []string{"20%", "bold    done"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("view() = %q, want %q", got, want)
+	}
+}
+
+func TestScanProgress(t *testing.T) {
+	pattern := regexp.MustCompile(defaultProgress)
+	for output, want := range map[string]float64{
+		"":                     -1,
+		"no progress":          -1,
+		"10% then 42.5%":       42.5,
+		"downloading... 130%":  100,
+		"step 3/4, 75% done\n": 75,
+	} {
+		if got := scanProgress(pattern, output); got != want {
+			t.Errorf("scanProgress(%q) = %v, want %v", output, got, want)
+		}
+	}
+
+	// Without a group the whole match is the percentage.
+	if got := scanProgress(regexp.MustCompile(`\d+%`), "at 30%"); got != 30 {
+		t.Errorf("scanProgress() = %v, want 30", got)
+	}
+}
+
+func TestProgressView(t *testing.T) {
+	for _, tt := range []struct {
+		percent float64
+		elapsed time.Duration
+		since   time.Duration
+		want    string
+	}{
+		{0, 0, 0, "  0%"},
+		{25, time.Minute, 0, " 25% ETA 3m0s"},
+		{25, time.Minute, 4 * time.Minute, " 25% ETA 0s"},
+		{100, time.Minute, 0, "100%"},
+	} {
+		// The bar is followed by the percentage and the time left.
+		got := progressView(tt.percent, tt.elapsed, tt.since)
+		if !strings.HasSuffix(got, tt.want) {
+			t.Errorf("progressView(%v, %v, %v) = %q, want it to end with %q", tt.percent, tt.elapsed, tt.since, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package spin
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// statusTimeout is the exit status when the command is killed because it ran
+// out of time, the same as that of timeout(1).
+const statusTimeout = 124
+
+// killGrace is how long the command has to exit once it is asked to, before
+// it is killed.
+const killGrace = 5 * time.Second
+
+// Like in shells, the exit status is 127 when the command is not found, 126
+// when it can't be run, and 128 plus the signal when it was killed by one.
+const (
+	statusNotFound    = 127
+	statusCannotRun   = 126
+	statusSignalFirst = 128
+)
+
+// run runs the command and returns its exit status. The command (and the
+// processes it started) is terminated once the timeout runs out, if any. Why
+// the command could not be started is written to its stderr.
+func run(cmd *exec.Cmd, timeout time.Duration) int {
+	if timeout > 0 {
+		setProcessGroup(cmd)
+	}
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintln(cmd.Stderr, err)
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+			return statusNotFound
+		}
+		return statusCannotRun
+	}
+
+	var timedOut int32
+	done := make(chan struct{})
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			atomic.StoreInt32(&timedOut, 1)
+			_ = terminate(cmd)
+			select {
+			case <-done:
+			case <-time.After(killGrace):
+				_ = kill(cmd)
+			}
+		})
+		defer timer.Stop()
+	}
+
+	_ = cmd.Wait()
+	close(done)
+
+	if atomic.LoadInt32(&timedOut) == 1 {
+		return statusTimeout
+	}
+	if ws, ok := cmd.ProcessState.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return statusSignalFirst + int(ws.Signal())
+	}
+	return cmd.ProcessState.ExitCode()
+}
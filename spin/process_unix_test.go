@@ -0,0 +1,53 @@
+//go:build !windows
+// +build !windows
+
+package spin
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunStatus(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "script")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		command []string
+		timeout time.Duration
+		want    int
+	}{
+		{"success", []string{"true"}, 0, 0},
+		{"failure", []string{"sh", "-c", "exit 3"}, 0, 3},
+		{"not found", []string{"gum-no-such-command"}, 0, statusNotFound},
+		{"not executable", []string{script}, 0, statusCannotRun},
+		{"signaled", []string{"sh", "-c", "kill -TERM $$"}, 0, statusSignalFirst + 15},
+		{"timeout", []string{"sleep", "5"}, 100 * time.Millisecond, statusTimeout},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(tt.command[0], tt.command[1:]...) //nolint:gosec
+			var stderr strings.Builder
+			cmd.Stderr = &stderr
+			if got := run(cmd, tt.timeout); got != tt.want {
+				t.Errorf("run() = %d, want %d (stderr %q)", got, tt.want, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunWritesWhyItCouldNotStart(t *testing.T) {
+	cmd := exec.Command("gum-no-such-command")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	run(cmd, 0)
+	if !strings.Contains(stderr.String(), "gum-no-such-command") {
+		t.Errorf("stderr = %q, want the reason", stderr.String())
+	}
+}
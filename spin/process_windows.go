@@ -0,0 +1,20 @@
+//go:build windows
+// +build windows
+
+package spin
+
+import "os/exec"
+
+// setProcessGroup does nothing, as there are no process groups to terminate
+// on Windows.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminate kills the command, which can't be asked to exit on Windows.
+func terminate(cmd *exec.Cmd) error {
+	return cmd.Process.Kill() //nolint:wrapcheck
+}
+
+// kill kills the command.
+func kill(cmd *exec.Cmd) error {
+	return cmd.Process.Kill() //nolint:wrapcheck
+}
@@ -0,0 +1,37 @@
+package spin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// result is the outcome of a command, as printed with --json. The title is
+// only set for tasks.
+type result struct {
+	Title      string `json:"title,omitempty"`
+	ExitCode   int    `json:"exit_code"`
+	DurationMs int64  `json:"duration_ms"`
+	Stdout     string `json:"stdout"`
+	Stderr     string `json:"stderr"`
+}
+
+func newResult(status int, elapsed time.Duration, stdout, stderr string) result {
+	return result{
+		ExitCode:   status,
+		DurationMs: elapsed.Milliseconds(),
+		Stdout:     stdout,
+		Stderr:     stderr,
+	}
+}
+
+// printJSON prints the result, or the results of the tasks, as JSON.
+func printJSON(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode the result: %w", err)
+	}
+	fmt.Fprintln(os.Stdout, string(b))
+	return nil
+}
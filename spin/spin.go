@@ -15,31 +15,78 @@
 package spin
 
 import (
+	"fmt"
+	"io"
 	"os/exec"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
 )
 
 type model struct {
-	spinner spinner.Model
-	title   string
-	command []string
-	aborted bool
+	spinner  spinner.Model
+	title    string
+	command  []string
+	timeout  time.Duration
+	aborted  bool
+	quitting bool
+
+	// The last lines of the output are displayed below the spinner, cut to
+	// the width of the terminal.
+	output      chan outputMsg
+	tail        tail
+	outputStyle lipgloss.Style
+	width       int
+
+	// The progress bar takes the place of the spinner once the progress
+	// pattern matches a percentage in the output. Until then, the percent is
+	// -1. The end of the last chunk of output is kept to be matched along
+	// with the next one. The time left is estimated from when the percent
+	// last changed, and when the attempt started.
+	progress       *regexp.Regexp
+	percent        float64
+	progressed     time.Time
+	progressTail   string
+	started        time.Time
+	attemptStarted time.Time
+	spinnerStyle   lipgloss.Style
+
+	// The time elapsed since the start is displayed next to the title. Once
+	// the command is done, the spinner is replaced by whether it succeeded,
+	// and kept on screen if asked to.
+	showElapsed  bool
+	elapsedStyle lipgloss.Style
+	keep         bool
+	done         bool
+	elapsed      time.Duration
+
+	// A command that fails is run again after the retry delay, until it has
+	// been run the retries more times.
+	retries    int
+	retryDelay time.Duration
+	attempt    int
 
 	status int
 	stdout string
 	stderr string
 }
 
+type retryMsg struct{}
+
 type finishCommandMsg struct {
 	stdout string
 	stderr string
 	status int
 }
 
-func commandStart(command []string) tea.Cmd {
+// commandStart runs the command. Its output is sent along as it runs if there
+// is a writer for it.
+func commandStart(command []string, output io.Writer, timeout time.Duration) tea.Cmd {
 	return func() tea.Msg {
 		var args []string
 		if len(command) > 1 {
@@ -50,15 +97,13 @@ func commandStart(command []string) tea.Cmd {
 		var outbuf, errbuf strings.Builder
 		cmd.Stdout = &outbuf
 		cmd.Stderr = &errbuf
-
-		_ = cmd.Run()
-
-		status := cmd.ProcessState.ExitCode()
-
-		if status == -1 {
-			status = 1
+		if output != nil {
+			cmd.Stdout = io.MultiWriter(&outbuf, output)
+			cmd.Stderr = io.MultiWriter(&errbuf, output)
 		}
 
+		status := run(cmd, timeout)
+
 		return finishCommandMsg{
 			stdout: outbuf.String(),
 			stderr: errbuf.String(),
@@ -68,21 +113,85 @@ func commandStart(command []string) tea.Cmd {
 }
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(
-		m.spinner.Tick,
-		commandStart(m.command),
-	)
+	if m.output != nil {
+		return tea.Batch(m.spinner.Tick, m.start(), waitForOutput(m.output))
+	}
+	return tea.Batch(m.spinner.Tick, m.start())
+}
+
+// start runs the command, with its output sent along if it is displayed.
+func (m model) start() tea.Cmd {
+	if m.output != nil {
+		return commandStart(m.command, outputWriter(m.output), m.timeout)
+	}
+	return commandStart(m.command, nil, m.timeout)
+}
+
+func (m model) View() string {
+	if m.quitting {
+		return ""
+	}
+	if m.done {
+		return summary(m.title, m.status, m.elapsed, m.elapsedStyle)
+	}
+
+	title := m.title
+	if m.attempt > 1 {
+		title += fmt.Sprintf(" (attempt %d/%d)", m.attempt, m.retries+1)
+	}
+	s := m.spinner.View() + " " + title
+	if m.percent >= 0 {
+		s = m.spinnerStyle.Render(progressView(m.percent, m.progressed.Sub(m.attemptStarted), time.Since(m.progressed))) + " " + title
+	}
+	if m.showElapsed {
+		s += " " + m.elapsedStyle.Render(time.Since(m.started).Round(time.Second).String())
+	}
+	for _, line := range m.tail.view() {
+		if m.width > 0 {
+			line = truncate.String(line, uint(m.width))
+		}
+		s += "\n" + m.outputStyle.Render(line)
+	}
+	return s
 }
-func (m model) View() string { return m.spinner.View() + " " + m.title }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case finishCommandMsg:
+		if msg.status != 0 && m.attempt <= m.retries {
+			m.attempt++
+			return m, tea.Tick(m.retryDelay, func(time.Time) tea.Msg { return retryMsg{} })
+		}
 		m.stdout = msg.stdout
 		m.stderr = msg.stderr
 		m.status = msg.status
+		m.elapsed = time.Since(m.started)
+		m.done = true
+		m.quitting = !m.keep
 		return m, tea.Quit
+	case retryMsg:
+		// The attempt starts over, without the output and progress of the
+		// last one.
+		m.tail = tail{size: m.tail.size}
+		m.percent = -1
+		m.progressed = time.Time{}
+		m.progressTail = ""
+		m.attemptStarted = time.Now()
+		return m, m.start()
+	case outputMsg:
+		m.tail.write(msg)
+		if m.progress != nil {
+			output := m.progressTail + string(msg)
+			if percent := scanProgress(m.progress, output); percent >= 0 && percent != m.percent {
+				m.percent = percent
+				m.progressed = time.Now()
+			}
+			m.progressTail = output[max(0, len(output)-carry):]
+		}
+		return m, waitForOutput(m.output)
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c":
@@ -94,3 +203,29 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	m.spinner, cmd = m.spinner.Update(msg)
 	return m, cmd
 }
+
+// summary returns the line that replaces the spinner once the command is
+// done, with whether it succeeded and how long it took. The last line is
+// cleared as gum exits, so the summary ends with an empty one.
+func summary(title string, status int, elapsed time.Duration, elapsedStyle lipgloss.Style) string {
+	mark := successStyle.Render("✓")
+	if status != 0 {
+		mark = failureStyle.Render("✗")
+	}
+	return mark + " " + title + " " + elapsedStyle.Render("("+elapsed.Round(elapsedPrecision).String()+")") + "\n"
+}
+
+// elapsedPrecision is the precision of the time the command took.
+const elapsedPrecision = 100 * time.Millisecond
+
+var (
+	successStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	failureStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
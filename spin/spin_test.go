@@ -0,0 +1,37 @@
+package spin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryStartsOver(t *testing.T) {
+	m := model{
+		command:    []string{"true"},
+		tail:       tail{size: 2},
+		percent:    -1,
+		retries:    1,
+		retryDelay: time.Millisecond,
+		attempt:    1,
+	}
+	tm, _ := m.Update(outputMsg("50%\nhalf"))
+	m = tm.(model)
+	m.percent = 50
+	m.progressTail = "half"
+
+	tm, _ = m.Update(finishCommandMsg{status: 1})
+	tm, _ = tm.Update(retryMsg{})
+	m = tm.(model)
+	if m.attempt != 2 {
+		t.Errorf("attempt = %d, want 2", m.attempt)
+	}
+	if lines := m.tail.view(); len(lines) != 0 {
+		t.Errorf("tail = %q, want none", lines)
+	}
+	if m.percent != -1 || m.progressTail != "" || !m.progressed.IsZero() {
+		t.Errorf("progress = %v %q %v, want none", m.percent, m.progressTail, m.progressed)
+	}
+	if m.tail.size != 2 {
+		t.Errorf("tail size = %d, want 2", m.tail.size)
+	}
+}
@@ -1,6 +1,13 @@
 package spin
 
-import "github.com/charmbracelet/bubbles/spinner"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+)
 
 var spinnerMap = map[string]spinner.Spinner{
 	"line":      spinner.Line,
@@ -15,3 +22,70 @@ var spinnerMap = map[string]spinner.Spinner{
 	"meter":     spinner.Meter,
 	"hamburger": spinner.Hamburger,
 }
+
+// spinnerFile is a spinner of a spinners file, which is a JSON object of the
+// spinners by their names, such as:
+//
+//	{"brand": {"frames": ["⠁", "⠂", "⠄"], "interval": "80ms"}}
+type spinnerFile struct {
+	Frames   []string `json:"frames"`
+	Interval string   `json:"interval"`
+}
+
+// readSpinners returns the spinners of the spinners file by their names.
+func readSpinners(path string) (map[string]spinner.Spinner, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read spinners: %w", err)
+	}
+	var file map[string]spinnerFile
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("unable to read spinners: %w", err)
+	}
+
+	spinners := make(map[string]spinner.Spinner, len(file))
+	for name, s := range file {
+		if len(s.Frames) == 0 {
+			return nil, fmt.Errorf("spinner %q has no frames", name)
+		}
+		interval := defaultInterval
+		if s.Interval != "" {
+			interval, err = time.ParseDuration(s.Interval)
+			if err != nil {
+				return nil, fmt.Errorf("invalid interval of spinner %q: %w", name, err)
+			}
+		}
+		spinners[name] = spinner.Spinner{Frames: s.Frames, FPS: interval}
+	}
+	return spinners, nil
+}
+
+// defaultInterval is the time between the frames of a custom spinner, unless
+// it is given.
+const defaultInterval = 100 * time.Millisecond
+
+// spinner returns the spinner to display, either a built-in one or one of the
+// spinners file, along with the frames and interval that replace its own.
+func (o Options) spinner() (spinner.Spinner, error) {
+	s, ok := spinnerMap[o.Spinner]
+	if o.Spinners != "" {
+		spinners, err := readSpinners(o.Spinners)
+		if err != nil {
+			return s, err
+		}
+		if custom, found := spinners[o.Spinner]; found {
+			s, ok = custom, true
+		}
+	}
+	if !ok {
+		return s, fmt.Errorf("unknown spinner %q", o.Spinner)
+	}
+
+	if len(o.SpinnerFrames) > 0 {
+		s.Frames = o.SpinnerFrames
+	}
+	if o.SpinnerInterval > 0 {
+		s.FPS = o.SpinnerInterval
+	}
+	return s, nil
+}
@@ -0,0 +1,166 @@
+package spin
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// task is a command that runs along with the others, under its own title.
+type task struct {
+	title   string
+	command string
+
+	started time.Time
+	done    bool
+	status  int
+	elapsed time.Duration
+	attempt int
+	stdout  string
+	stderr  string
+}
+
+// parseTask parses a task of the form TITLE::COMMAND. Without a title, the
+// command is its own title.
+func parseTask(s string) task {
+	parts := strings.SplitN(s, "::", 2) //nolint:gomnd
+	if len(parts) == 1 {
+		return task{title: s, command: s}
+	}
+	return task{title: parts[0], command: parts[1]}
+}
+
+type taskRetryMsg struct{ index int }
+
+type taskDoneMsg struct {
+	index  int
+	stdout string
+	stderr string
+	status int
+}
+
+// taskStart runs the command of a task with the shell.
+func taskStart(index int, command string, timeout time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("sh", "-c", command) //nolint:gosec
+
+		var outbuf, errbuf strings.Builder
+		cmd.Stdout = &outbuf
+		cmd.Stderr = &errbuf
+
+		status := run(cmd, timeout)
+
+		return taskDoneMsg{
+			index:  index,
+			stdout: outbuf.String(),
+			stderr: errbuf.String(),
+			status: status,
+		}
+	}
+}
+
+// tasksModel runs the tasks at the same time, with a line per task that shows
+// whether it is still running, succeeded or failed.
+type tasksModel struct {
+	spinner    spinner.Model
+	tasks      []task
+	timeout    time.Duration
+	retries    int
+	retryDelay time.Duration
+	titleStyle lipgloss.Style
+	aborted    bool
+	done       bool
+
+	// The lines are cleared once the tasks are done, unless they are kept.
+	keep         bool
+	showElapsed  bool
+	elapsedStyle lipgloss.Style
+}
+
+func (m tasksModel) Init() tea.Cmd {
+	cmds := []tea.Cmd{m.spinner.Tick}
+	for i, t := range m.tasks {
+		cmds = append(cmds, taskStart(i, t.command, m.timeout))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m tasksModel) View() string {
+	if m.done && !m.keep {
+		return ""
+	}
+
+	var s strings.Builder
+	for i, t := range m.tasks {
+		if i > 0 {
+			s.WriteString("\n")
+		}
+		if t.done {
+			s.WriteString(strings.TrimSuffix(summary(m.titleStyle.Render(t.title), t.status, t.elapsed, m.elapsedStyle), "\n"))
+			continue
+		}
+		s.WriteString(m.spinner.View() + " " + m.titleStyle.Render(t.title))
+		if t.attempt > 0 {
+			s.WriteString(m.titleStyle.Render(fmt.Sprintf(" (attempt %d/%d)", t.attempt+1, m.retries+1)))
+		}
+		if m.showElapsed {
+			s.WriteString(" " + m.elapsedStyle.Render(time.Since(t.started).Round(time.Second).String()))
+		}
+	}
+
+	// Kept lines stay on screen once the tasks are done, the last line is
+	// cleared as gum exits, so it has to be an empty one.
+	if m.done {
+		s.WriteString("\n")
+	}
+	return s.String()
+}
+
+func (m tasksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case taskRetryMsg:
+		return m, taskStart(msg.index, m.tasks[msg.index].command, m.timeout)
+	case taskDoneMsg:
+		t := &m.tasks[msg.index]
+		if msg.status != 0 && t.attempt < m.retries {
+			t.attempt++
+			return m, tea.Tick(m.retryDelay, func(time.Time) tea.Msg { return taskRetryMsg{msg.index} })
+		}
+		t.done = true
+		t.status = msg.status
+		t.elapsed = time.Since(t.started)
+		t.stdout = msg.stdout
+		t.stderr = msg.stderr
+		for _, t := range m.tasks {
+			if !t.done {
+				return m, nil
+			}
+		}
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.aborted = true
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return m, cmd
+}
+
+// status returns the exit status of the first task that failed, if any.
+func (m tasksModel) status() int {
+	for _, t := range m.tasks {
+		if t.status != 0 {
+			return t.status
+		}
+	}
+	return 0
+}
@@ -0,0 +1,37 @@
+package spin
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTasksKeep(t *testing.T) {
+	m := tasksModel{tasks: []task{{title: "a", started: time.Now()}}}
+	tm, _ := m.Update(taskDoneMsg{index: 0})
+	m = tm.(tasksModel)
+	if !m.done {
+		t.Fatal("tasks are not done")
+	}
+	if view := m.View(); view != "" {
+		t.Errorf("view = %q, want it cleared", view)
+	}
+
+	m.keep = true
+	if view := m.View(); !strings.Contains(view, "a") {
+		t.Errorf("view = %q, want the task kept", view)
+	}
+}
+
+func TestParseTask(t *testing.T) {
+	for s, want := range map[string]task{
+		"sleep 1":        {title: "sleep 1", command: "sleep 1"},
+		"Nap::sleep 1":   {title: "Nap", command: "sleep 1"},
+		"Nap::echo a::b": {title: "Nap", command: "echo a::b"},
+		"::sleep 1":      {title: "", command: "sleep 1"},
+	} {
+		if got := parseTask(s); got != want {
+			t.Errorf("parseTask(%q) = %+v, want %+v", s, got, want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package write
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -16,8 +17,17 @@ import (
 // Run provides a shell script interface for the text area bubble.
 // https://github.com/charmbracelet/bubbles/textarea
 func (o Options) Run() error {
-	in, _ := stdin.Read()
-	if in != "" && o.Value == "" {
+	if o.WriteInPlace && o.Path == "" {
+		return errors.New("--write-in-place requires a file to write to, see --path")
+	}
+
+	if o.Path != "" {
+		text, err := readFile(o.Path)
+		if err != nil {
+			return err
+		}
+		o.Value = text
+	} else if in, _ := stdin.Read(); in != "" && o.Value == "" {
 		o.Value = in
 	}
 
@@ -43,20 +53,89 @@ func (o Options) Run() error {
 	a.FocusedStyle = style
 	a.Cursor.Style = o.CursorStyle.ToLipgloss()
 
-	a.SetWidth(o.Width)
+	var fields []field
+	if o.Template {
+		o.Value, fields = parseTemplate(o.Value)
+	}
+
+	// The text of a previous session that was not completed is offered to
+	// be restored in place of the initial value.
+	var path, recovered string
+	if o.RecoverKey != "" {
+		var err error
+		path, err = recoverPath(o.RecoverKey)
+		if err != nil {
+			return err
+		}
+		recovered = readRecovery(path)
+	}
+	restoring := recovered != "" && recovered != o.Value
+
 	a.SetHeight(o.Height)
-	a.SetValue(o.Value)
+	if restoring {
+		a.SetValue(recovered)
+	} else {
+		a.SetValue(o.Value)
+	}
+
+	var h *highlighter
+	if o.Language != "" {
+		var err error
+		h, err = newHighlighter(o.Language, o.Theme)
+		if err != nil {
+			return err
+		}
+	}
 
-	p := tea.NewProgram(model{textarea: a}, tea.WithOutput(os.Stderr))
+	m := model{
+		textarea:    a,
+		style:       style,
+		width:       o.Width,
+		softWrap:    o.SoftWrap,
+		highlighter: h,
+		showStatus:  o.ShowStatus,
+		statusStyle: o.StatusStyle.ToLipgloss(),
+		fields:      fields,
+		field:       -1,
+		fieldStyle:  o.FieldStyle.ToLipgloss(),
+		recoverPath: path,
+		saved:       recovered,
+		restoring:   restoring,
+		original:    o.Value,
+
+		lineLimit:    o.LineLimit,
+		warningStyle: o.WarningStyle.ToLipgloss(),
+
+		formatCmd:  o.FormatCmd,
+		errorStyle: o.ErrorStyle.ToLipgloss(),
+	}
+	if o.Vim {
+		m.vim = &vim{insert: true}
+	}
+	m.setWidth()
+	if len(fields) > 0 && !restoring {
+		m.jump(1)
+	}
+	m.scroll()
+
+	p := tea.NewProgram(m, tea.WithOutput(os.Stderr))
 	tm, err := p.StartReturningModel()
 	if err != nil {
 		return fmt.Errorf("failed to run write: %w", err)
 	}
-	m := tm.(model)
+	m = tm.(model)
 	if m.aborted {
 		return exit.ErrAborted
 	}
 
+	// The text was completed, there is nothing left to recover.
+	if path != "" {
+		_ = os.Remove(path)
+	}
+
+	if o.WriteInPlace {
+		return writeFile(o.Path, m.textarea.Value())
+	}
 	fmt.Println(m.textarea.Value())
 	return nil
 }
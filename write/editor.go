@@ -0,0 +1,63 @@
+package write
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type editorMsg struct {
+	path string
+	err  error
+}
+
+// openEditor suspends the text area to edit the text in the editor of the
+// user instead, from $VISUAL or $EDITOR.
+func openEditor(text string) tea.Cmd {
+	f, err := os.CreateTemp("", "gum-write-*.txt")
+	if err != nil {
+		return func() tea.Msg { return editorMsg{err: err} }
+	}
+	path := f.Name()
+	_, err = f.WriteString(text)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return func() tea.Msg { return editorMsg{path: path, err: err} }
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// The editor may come with arguments of its own, such as "code --wait".
+	args := append(strings.Fields(editor), path)
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorMsg{path: path, err: err}
+	})
+}
+
+// readEditor returns the text that was edited in the editor, and removes the
+// file it was edited in.
+func readEditor(msg editorMsg) (string, error) {
+	if msg.path != "" {
+		defer os.Remove(msg.path) //nolint:errcheck
+	}
+	if msg.err != nil {
+		return "", msg.err
+	}
+	b, err := os.ReadFile(msg.path)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+	// Editors usually end the file with a newline.
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
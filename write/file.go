@@ -0,0 +1,33 @@
+package write
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// readFile returns the text of the file, without the newline it ends with. A
+// missing file has no text yet, it is created when the text is written back.
+func readFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to read file: %w", err)
+	}
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+// writeFile writes the text back to the file, ending with a newline. An
+// existing file keeps its permissions.
+func writeFile(path, text string) error {
+	mode := os.FileMode(0o644) //nolint:gomnd
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(path, []byte(text+"\n"), mode); err != nil {
+		return fmt.Errorf("unable to write file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package write
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type formatMsg struct {
+	text string
+	err  error
+}
+
+// format pipes the text through the format command, which prints the text in
+// its format. The errors of the command are what it printed to stderr.
+func format(command, text string) tea.Cmd {
+	return func() tea.Msg {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command("sh", "-c", command) //nolint:gosec
+		cmd.Stdin = strings.NewReader(text + "\n")
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				err = errors.New(msg)
+			}
+			return formatMsg{err: err}
+		}
+		return formatMsg{text: strings.TrimSuffix(stdout.String(), "\n")}
+	}
+}
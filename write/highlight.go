@@ -0,0 +1,71 @@
+package write
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/chroma"
+	"github.com/alecthomas/chroma/lexers"
+	"github.com/alecthomas/chroma/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// highlighter applies syntax highlighting to the text, with chroma.
+// https://github.com/alecthomas/chroma
+type highlighter struct {
+	lexer chroma.Lexer
+	style *chroma.Style
+}
+
+// newHighlighter returns a highlighter for the language in the given theme.
+func newHighlighter(language, theme string) (*highlighter, error) {
+	lexer := lexers.Get(language)
+	if lexer == nil {
+		return nil, fmt.Errorf("unknown language %q", language)
+	}
+	style := styles.Get(theme)
+	if style == nil {
+		style = styles.Fallback
+	}
+	return &highlighter{lexer: chroma.Coalesce(lexer), style: style}, nil
+}
+
+// styles returns the style of every character of every line of the text.
+func (h highlighter) styles(text string) [][]lipgloss.Style {
+	lines := [][]lipgloss.Style{nil}
+
+	tokens, err := h.lexer.Tokenise(nil, text)
+	if err != nil {
+		return lines
+	}
+	for token := tokens(); token != chroma.EOF; token = tokens() {
+		style := h.lipgloss(h.style.Get(token.Type))
+		for i, line := range strings.Split(token.Value, "\n") {
+			if i > 0 {
+				lines = append(lines, nil)
+			}
+			for range line {
+				lines[len(lines)-1] = append(lines[len(lines)-1], style)
+			}
+		}
+	}
+	return lines
+}
+
+// lipgloss converts the style of a token to a Lip Gloss style.
+func (h highlighter) lipgloss(entry chroma.StyleEntry) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	if entry.Colour.IsSet() {
+		style = style.Foreground(lipgloss.Color(entry.Colour.String()))
+	}
+	if entry.Bold == chroma.Yes {
+		style = style.Bold(true)
+	}
+	if entry.Italic == chroma.Yes {
+		style = style.Italic(true)
+	}
+	if entry.Underline == chroma.Yes {
+		style = style.Underline(true)
+	}
+	return style
+}
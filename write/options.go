@@ -10,8 +10,19 @@ type Options struct {
 	Prompt          string `help:"Prompt to display" default:"┃ " env:"GUM_WRITE_PROMPT"`
 	ShowCursorLine  bool   `help:"Show cursor line" default:"false" env:"GUM_WRITE_SHOW_CURSOR_LINE"`
 	ShowLineNumbers bool   `help:"Show line numbers" default:"false" env:"GUM_WRITE_SHOW_LINE_NUMBERS"`
+	SoftWrap        bool   `help:"Wrap the lines that are wider than the text area" default:"true" negatable:"" env:"GUM_WRITE_SOFT_WRAP"`
+	ShowStatus      bool   `help:"Show the position of the cursor and the counts of the text below the text area" default:"false" env:"GUM_WRITE_SHOW_STATUS"`
+	Vim             bool   `help:"Edit the text with the keys of vim, starting in insert mode" default:"false" env:"GUM_WRITE_VIM"`
+	Template        bool   `help:"Type over the fields of the initial value, such as ${1:scope}, moving between them with tab" default:"false" env:"GUM_WRITE_TEMPLATE"`
+	RecoverKey      string `help:"Save the text every few seconds to restore it the next time the same key is given, unless it was completed" default:"" env:"GUM_WRITE_RECOVER_KEY"`
+	FormatCmd       string `help:"Command to pipe the text through to format it with ctrl+f, such as gofmt" default:"" env:"GUM_WRITE_FORMAT_CMD"`
 	Value           string `help:"Initial value (can be passed via stdin)" default:"" env:"GUM_WRITE_VALUE"`
+	Path            string `help:"File to load the initial value from" default:"" env:"GUM_WRITE_PATH"`
+	WriteInPlace    bool   `help:"Write the value back to the file of --path instead of stdout" default:"false" env:"GUM_WRITE_WRITE_IN_PLACE"`
 	CharLimit       int    `help:"Maximum value length (0 for no limit)" default:"400"`
+	LineLimit       int    `help:"Maximum number of lines (0 for no limit)" default:"0" env:"GUM_WRITE_LINE_LIMIT"`
+	Language        string `help:"Language to highlight the syntax of, such as go, yaml or markdown" default:"" env:"GUM_WRITE_LANGUAGE"`
+	Theme           string `help:"Theme of the syntax highlighting" default:"monokai" env:"GUM_WRITE_THEME"`
 
 	BaseStyle             style.Styles `embed:"" prefix:"base." envprefix:"GUM_WRITE_BASE_"`
 	CursorLineNumberStyle style.Styles `embed:"" prefix:"cursor-line-number." set:"defaultForeground=7" envprefix:"GUM_WRITE_CURSOR_LINE_NUMBER_"`
@@ -20,5 +31,9 @@ type Options struct {
 	EndOfBufferStyle      style.Styles `embed:"" prefix:"end-of-buffer." set:"defaultForeground=0" envprefix:"GUM_WRITE_END_OF_BUFFER_"`
 	LineNumberStyle       style.Styles `embed:"" prefix:"line-number." set:"defaultForeground=7" envprefix:"GUM_WRITE_LINE_NUMBER_"`
 	PlaceholderStyle      style.Styles `embed:"" prefix:"placeholder." set:"defaultForeground=240" envprefix:"GUM_WRITE_PLACEHOLDER_"`
+	FieldStyle            style.Styles `embed:"" prefix:"field." set:"defaultForeground=212" set:"defaultUnderline=true" envprefix:"GUM_WRITE_FIELD_"`
+	ErrorStyle            style.Styles `embed:"" prefix:"error." set:"defaultForeground=9" envprefix:"GUM_WRITE_ERROR_"`
+	WarningStyle          style.Styles `embed:"" prefix:"warning." set:"defaultForeground=214" envprefix:"GUM_WRITE_WARNING_"`
+	StatusStyle           style.Styles `embed:"" prefix:"status." set:"defaultForeground=240" envprefix:"GUM_WRITE_STATUS_"`
 	PromptStyle           style.Styles `embed:"" prefix:"prompt." set:"defaultForeground=7" envprefix:"GUM_WRITE_PROMPT_"`
 }
@@ -0,0 +1,58 @@
+package write
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// autosaveInterval is how often the text is saved to be recovered.
+const autosaveInterval = 5 * time.Second
+
+type autosaveMsg struct{}
+
+func autosave() tea.Cmd {
+	return tea.Tick(autosaveInterval, func(time.Time) tea.Msg {
+		return autosaveMsg{}
+	})
+}
+
+// unsafeKey matches the characters of a recover key that can't be part of a
+// file name.
+var unsafeKey = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// recoverPath returns the path of the file the text is saved in to be
+// recovered with the given key.
+func recoverPath(key string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find the recovery directory: %w", err)
+	}
+	return filepath.Join(dir, "gum", "write", unsafeKey.ReplaceAllString(key, "_")+".txt"), nil
+}
+
+// readRecovery returns the text that was saved to be recovered, if any.
+func readRecovery(path string) string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// saveRecovery saves the text to be recovered. The text is written to a
+// temporary file first, so that it is never saved halfway.
+func saveRecovery(path, text string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil { //nolint:gomnd
+		return err //nolint:wrapcheck
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(text), 0o600); err != nil { //nolint:gomnd
+		return err //nolint:wrapcheck
+	}
+	return os.Rename(tmp, path) //nolint:wrapcheck
+}
@@ -0,0 +1,159 @@
+package write
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// templateField matches the fields of a template, such as ${1:scope}, which
+// are typed over in the order of their numbers.
+var templateField = regexp.MustCompile(`\$\{(\d+):([^}]*)\}`)
+
+// field is a field of a template, which holds the text from start up to (but
+// excluding) end. The text of the field is replaced by what is typed in it,
+// until then the field is displayed in its own style.
+type field struct {
+	number     int
+	start, end int
+	filled     bool
+}
+
+// parseTemplate returns the text of the template without the markup of its
+// fields, along with the fields in the order of their numbers.
+func parseTemplate(template string) (string, []field) {
+	var (
+		s      strings.Builder
+		fields []field
+		last   int
+	)
+	for _, loc := range templateField.FindAllStringSubmatchIndex(template, -1) {
+		s.WriteString(template[last:loc[0]])
+		number, _ := strconv.Atoi(template[loc[2]:loc[3]])
+		start := utf8.RuneCountInString(s.String())
+		s.WriteString(template[loc[4]:loc[5]])
+		fields = append(fields, field{
+			number: number,
+			start:  start,
+			end:    start + utf8.RuneCountInString(template[loc[4]:loc[5]]),
+		})
+		last = loc[1]
+	}
+	s.WriteString(template[last:])
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].number < fields[j].number
+	})
+	return s.String(), fields
+}
+
+// jump moves the cursor to the next field of the template, or the previous
+// one, wrapping around. The cursor goes to the start of a field that has not
+// been typed over yet, and to the end of one that has.
+func (m *model) jump(d int) {
+	n := len(m.fields)
+	m.field = ((m.field+d)%n + n) % n
+	f := m.fields[m.field]
+
+	text, _ := m.text()
+	if f.filled {
+		m.setCursor(text, f.end)
+	} else {
+		m.setCursor(text, f.start)
+	}
+}
+
+// typeOver clears the current field if it has not been typed over yet and the
+// cursor is in it, so that what is typed replaces its text. It returns false
+// if the field was left as it was.
+func (m *model) typeOver() bool {
+	if m.field < 0 || m.fields[m.field].filled {
+		return false
+	}
+	f := &m.fields[m.field]
+	text, p := m.text()
+	if p < f.start || p > f.end {
+		return false
+	}
+	f.filled = true
+	m.setText(remove(text, f.start, f.end), f.start)
+	return true
+}
+
+// shiftFields moves the fields of the template along with the text, which
+// has changed from before. The fields that the change touches are filled.
+func (m *model) shiftFields(before []rune) {
+	after := []rune(m.textarea.Value())
+
+	// The change is what is left between the common prefix and suffix.
+	var prefix, suffix int
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+	for suffix < len(before)-prefix && suffix < len(after)-prefix &&
+		before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+	oldEnd, newEnd := len(before)-suffix, len(after)-suffix
+	if oldEnd == prefix && newEnd == prefix {
+		return
+	}
+
+	delta := newEnd - oldEnd
+	for i := range m.fields {
+		f := &m.fields[i]
+		if oldEnd == prefix {
+			// Text inserted at the edge of fields goes to the current one.
+			switch {
+			case f.start < prefix && f.end > prefix, i == m.field && f.start <= prefix && f.end >= prefix:
+				f.filled = true
+				f.end += delta
+			case f.start >= prefix:
+				f.start += delta
+				f.end += delta
+			}
+			continue
+		}
+
+		switch {
+		case f.end <= prefix:
+		case f.start >= oldEnd:
+			f.start += delta
+			f.end += delta
+		default:
+			f.filled = true
+			f.start = min(f.start, prefix)
+			f.end = max(f.start, max(f.end+delta, newEnd))
+		}
+	}
+}
+
+// fieldStyles returns the styles of the characters of the lines with the
+// fields that have not been typed over yet in their style, on top of the
+// highlighted styles.
+func (m model) fieldStyles(lines []string, highlights [][]lipgloss.Style) [][]lipgloss.Style {
+	styles := make([][]lipgloss.Style, len(lines))
+	var p int
+	for l, line := range lines {
+		n := utf8.RuneCountInString(line)
+		styles[l] = make([]lipgloss.Style, n)
+		for c := 0; c < n; c++ {
+			if l < len(highlights) && c < len(highlights[l]) {
+				styles[l][c] = highlights[l][c]
+			} else {
+				styles[l][c] = lipgloss.NewStyle()
+			}
+			for _, f := range m.fields {
+				if !f.filled && p+c >= f.start && p+c < f.end {
+					styles[l][c] = m.fieldStyle.Copy().Inherit(styles[l][c])
+				}
+			}
+		}
+		p += n + 1
+	}
+	return styles
+}
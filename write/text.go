@@ -0,0 +1,94 @@
+package write
+
+import "strings"
+
+// text returns the text of the text area, along with the position of the
+// cursor in it.
+func (m model) text() ([]rune, int) {
+	text := []rune(m.textarea.Value())
+	info := m.textarea.LineInfo()
+	col := info.StartColumn + info.ColumnOffset
+
+	var p int
+	for row := 0; row < m.textarea.Line(); row++ {
+		p = lineEnd(text, p) + 1
+	}
+	return text, min(p+col, lineEnd(text, p))
+}
+
+// setText replaces the text of the text area and moves the cursor to the
+// given position.
+func (m *model) setText(text []rune, p int) {
+	m.textarea.SetValue(string(text))
+	m.setCursor(text, p)
+}
+
+// setCursor moves the cursor to the given position in the text.
+func (m *model) setCursor(text []rune, p int) {
+	p = clamp(p, 0, len(text))
+	start := lineStart(text, p)
+	row := strings.Count(string(text[:start]), "\n")
+
+	// The text area only moves up and down by the rows that the lines are
+	// wrapped on, which it gets wrong with wide characters. From the start of
+	// a line though, it moves to the end of the line above, so the cursor
+	// goes up line by line, from the last line if it is above the position.
+	if m.textarea.Line() < row {
+		m.textarea.SetValue(string(text))
+	}
+	for m.textarea.Line() > row {
+		m.textarea.CursorStart()
+		m.textarea.CursorUp()
+	}
+	m.textarea.SetCursor(p - start)
+}
+
+// moveLine moves the cursor to the same column of the line below, or the one
+// above, as far as the line goes.
+func (m *model) moveLine(d int) {
+	text, p := m.text()
+	start := lineStart(text, p)
+	col := p - start
+	switch {
+	case d > 0 && lineEnd(text, p) < len(text):
+		start = lineEnd(text, p) + 1
+	case d < 0 && start > 0:
+		start = lineStart(text, start-1)
+	default:
+		return
+	}
+	m.setCursor(text, min(start+col, lineEnd(text, start)))
+}
+
+// lineStart returns the position of the first character of the line at p.
+func lineStart(text []rune, p int) int {
+	for p > 0 && text[p-1] != '\n' {
+		p--
+	}
+	return p
+}
+
+// lineEnd returns the position of the newline that ends the line at p, or the
+// end of the text.
+func lineEnd(text []rune, p int) int {
+	for p < len(text) && text[p] != '\n' {
+		p++
+	}
+	return p
+}
+
+// insert returns the text with the runes inserted at p.
+func insert(text []rune, p int, runes []rune) []rune {
+	out := make([]rune, 0, len(text)+len(runes))
+	out = append(out, text[:p]...)
+	out = append(out, runes...)
+	return append(out, text[p:]...)
+}
+
+// remove returns the text without the runes from start up to (but excluding)
+// end.
+func remove(text []rune, start, end int) []rune {
+	out := make([]rune, 0, len(text)-(end-start))
+	out = append(out, text[:start]...)
+	return append(out, text[end:]...)
+}
@@ -0,0 +1,375 @@
+package write
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	rw "github.com/mattn/go-runewidth"
+)
+
+// lineNumberFormat is the format of the line numbers of the text area.
+const lineNumberFormat = "%2v "
+
+// editorView renders the text area the same way as the text area itself does,
+// so that the text can be highlighted, or left unwrapped. Otherwise the text
+// area renders itself.
+func (m model) editorView() string {
+	ta := m.textarea
+	if m.softWrap && m.highlighter == nil && (len(m.fields) == 0 || m.restoring) {
+		return ta.View()
+	}
+	if ta.Value() == "" && ta.Placeholder != "" {
+		// Without soft wrap the text area is wider than it is displayed.
+		ta.SetWidth(m.width)
+		return ta.View()
+	}
+	m.column = m.scrollColumn()
+
+	var highlights [][]lipgloss.Style
+	if m.highlighter != nil {
+		highlights = m.highlighter.styles(ta.Value())
+	}
+	if len(m.fields) > 0 && !m.restoring {
+		highlights = m.fieldStyles(strings.Split(ta.Value(), "\n"), highlights)
+	}
+
+	width := ta.Width()
+	if !m.softWrap {
+		width = m.textWidth()
+	}
+
+	row := ta.Line()
+	info := ta.LineInfo()
+	col := info.StartColumn + info.ColumnOffset
+	cur := ta.Cursor
+
+	var s strings.Builder
+	for l, line := range strings.Split(ta.Value(), "\n") {
+		runes := []rune(line)
+
+		var highlight []lipgloss.Style
+		if l < len(highlights) {
+			highlight = highlights[l]
+		}
+
+		style := m.style.Text
+		if l == row {
+			style = m.style.CursorLine
+		}
+
+		rows, start := m.rows(runes)
+		for wl, wrappedLine := range rows {
+			end := start + len(wrappedLine)
+
+			s.WriteString(style.Render(m.style.Prompt.Render(ta.Prompt)))
+
+			if ta.ShowLineNumbers {
+				switch {
+				case wl > 0:
+					s.WriteString(m.style.LineNumber.Render(style.Render("   ")))
+				case l == row:
+					s.WriteString(style.Render(m.style.CursorLineNumber.Render(fmt.Sprintf(lineNumberFormat, l+1))))
+				default:
+					s.WriteString(style.Render(m.style.LineNumber.Render(fmt.Sprintf(lineNumberFormat, l+1))))
+				}
+			}
+
+			strwidth := rw.StringWidth(string(wrappedLine))
+			padding := width - strwidth
+			// The trailing space of a line that is exactly as wide as the
+			// text area is not drawn, so that it does not overflow.
+			if strwidth > width {
+				wrappedLine = []rune(strings.TrimSuffix(string(wrappedLine), " "))
+				padding = 0
+			}
+
+			// Without soft wrap, the text area still wraps the lines that
+			// are wider than it can be, so the cursor is placed by its column
+			// in the whole line rather than in the row it is wrapped on.
+			if l == row && (!m.softWrap || info.RowOffset == wl) {
+				offset := info.ColumnOffset
+				if !m.softWrap {
+					offset = col - start
+				}
+				s.WriteString(renderText(wrappedLine[:offset], highlight, start, style))
+				if m.softWrap && col >= len(runes) && info.CharOffset >= ta.Width() {
+					cur.TextStyle = style
+					cur.SetChar(" ")
+					s.WriteString(cur.View())
+				} else {
+					cur.TextStyle = tokenStyle(highlight, start+offset, style)
+					cur.SetChar(string(wrappedLine[offset]))
+					s.WriteString(style.Render(cur.View()))
+					s.WriteString(renderText(wrappedLine[offset+1:], highlight, start+offset+1, style))
+				}
+			} else {
+				s.WriteString(renderText(wrappedLine, highlight, start, style))
+			}
+			s.WriteString(style.Render(strings.Repeat(" ", max(0, padding))))
+			s.WriteRune('\n')
+
+			start = end
+		}
+	}
+
+	// Always show at least as many lines as the text area is high.
+	for i := 0; i < ta.Height(); i++ {
+		s.WriteString(m.style.Prompt.Render(ta.Prompt))
+		if ta.ShowLineNumbers {
+			s.WriteString(m.style.EndOfBuffer.Render(fmt.Sprintf(lineNumberFormat, string(ta.EndOfBufferCharacter))))
+		}
+		s.WriteRune('\n')
+	}
+
+	// Only the lines from the scroll offset on are in view.
+	lines := strings.Split(s.String(), "\n")
+	offset := clamp(m.scrollOffset(), 0, len(lines)-1)
+	lines = lines[offset:min(len(lines), offset+ta.Height())]
+
+	contents := lipgloss.NewStyle().
+		Height(ta.Height()).
+		MaxHeight(ta.Height()).
+		MaxWidth(clamp(m.width, minWidth, maxWidth)).
+		Render(strings.Join(lines, "\n"))
+	return m.style.Base.Render(contents)
+}
+
+// statusView renders the position of the cursor and the counts of the text,
+// along with the characters that remain if there is a limit.
+func (m model) statusView() string {
+	ta := m.textarea
+	info := ta.LineInfo()
+	value := ta.Value()
+
+	status := []string{
+		fmt.Sprintf("Ln %d, Col %d", ta.Line()+1, info.StartColumn+info.ColumnOffset+1),
+		plural(utf8.RuneCountInString(value), "char"),
+		plural(len(strings.Fields(value)), "word"),
+		plural(ta.LineCount(), "line"),
+	}
+	if ta.CharLimit > 0 {
+		status = append(status, fmt.Sprintf("%d left", max(0, ta.CharLimit-ta.Length())))
+	}
+	return m.statusStyle.Render(strings.Join(status, " · "))
+}
+
+// plural returns the count of things, such as "1 word" or "2 words".
+func plural(n int, thing string) string {
+	if n == 1 {
+		return "1 " + thing
+	}
+	return fmt.Sprintf("%d %ss", n, thing)
+}
+
+// scrollOffset returns the first line in view, scrolled as little as possible
+// from the current one to keep the cursor in view.
+func (m model) scrollOffset() int {
+	ta := m.textarea
+	lines := strings.Split(ta.Value(), "\n")
+
+	var line int
+	for i := 0; i < ta.Line() && i < len(lines); i++ {
+		if m.softWrap {
+			line += len(wrap([]rune(lines[i]), ta.Width()))
+		} else {
+			line++
+		}
+	}
+	if m.softWrap {
+		line += ta.LineInfo().RowOffset
+	}
+
+	switch {
+	case line < m.offset:
+		return line
+	case line >= m.offset+ta.Height():
+		return line - ta.Height() + 1
+	}
+	return m.offset
+}
+
+// scrollColumn returns the first character in view without soft wrap,
+// scrolled as little as possible from the current one to keep the cursor in
+// view. All lines are scrolled alike.
+func (m model) scrollColumn() int {
+	if m.softWrap {
+		return 0
+	}
+
+	ta := m.textarea
+	info := ta.LineInfo()
+	col := info.StartColumn + info.ColumnOffset
+	runes := []rune(strings.Split(ta.Value(), "\n")[ta.Line()])
+	if col < m.column {
+		return col
+	}
+
+	// The cursor takes a cell of its own at the end of the line.
+	cursorWidth := 1
+	if col < len(runes) {
+		cursorWidth = rw.RuneWidth(runes[col])
+	}
+	column := m.column
+	for column < col && rw.StringWidth(string(runes[column:col]))+cursorWidth > m.textWidth() {
+		column++
+	}
+	return column
+}
+
+// rows returns the rows that a line is displayed on, along with the index of
+// the first character displayed. Without soft wrap the line is displayed on a
+// single row, from the column scrolled to on.
+func (m model) rows(runes []rune) ([][]rune, int) {
+	if m.softWrap {
+		return wrap(runes, m.textarea.Width()), 0
+	}
+
+	// Like wrapped lines, the line ends with a space for the cursor.
+	runes = append(runes[:len(runes):len(runes)], ' ')
+	start := min(m.column, len(runes))
+	end := start
+	for end < len(runes) && rw.StringWidth(string(runes[start:end+1])) <= m.textWidth() {
+		end++
+	}
+	return [][]rune{runes[start:end]}, start
+}
+
+// textWidth returns the width of the text area without the prompt and line
+// numbers, the same way as the text area computes it.
+func (m model) textWidth() int {
+	w := m.width - rw.StringWidth(m.textarea.Prompt) - m.style.Base.GetHorizontalFrameSize()
+	if m.textarea.ShowLineNumbers {
+		w -= rw.StringWidth(fmt.Sprintf(lineNumberFormat, 0))
+	}
+	return clamp(w, minWidth, maxWidth)
+}
+
+// renderText renders the characters of a line from start on in their
+// highlighted styles, on top of the style of the line.
+func renderText(runes []rune, highlight []lipgloss.Style, start int, style lipgloss.Style) string {
+	if highlight == nil {
+		return style.Render(string(runes))
+	}
+
+	var s strings.Builder
+	for i := 0; i < len(runes); {
+		// Characters in the same style are rendered together.
+		j := i + 1
+		for j < len(runes) && sameStyle(highlight, start+i, start+j) {
+			j++
+		}
+		s.WriteString(tokenStyle(highlight, start+i, style).Render(string(runes[i:j])))
+		i = j
+	}
+	return s.String()
+}
+
+// tokenStyle returns the highlighted style of the i-th character of a line on
+// top of the style of the line.
+func tokenStyle(highlight []lipgloss.Style, i int, style lipgloss.Style) lipgloss.Style {
+	if i >= len(highlight) {
+		return style
+	}
+	return highlight[i].Copy().Inherit(style)
+}
+
+// sameStyle returns whether the i-th and j-th character of a line are
+// highlighted the same way.
+func sameStyle(highlight []lipgloss.Style, i, j int) bool {
+	if i >= len(highlight) || j >= len(highlight) {
+		return i >= len(highlight) && j >= len(highlight)
+	}
+	return highlight[i].String() == highlight[j].String()
+}
+
+// These limits are the same as those of the text area.
+const (
+	minWidth = 2
+	maxWidth = 500
+)
+
+// wrap soft-wraps the line at the given width, the same way as the text area
+// does. The wrapped lines hold all the characters of the line followed by a
+// space, in which the cursor is displayed at the end of the line.
+func wrap(runes []rune, width int) [][]rune {
+	var (
+		lines  = [][]rune{{}}
+		word   = []rune{}
+		row    int
+		spaces int
+	)
+
+	for _, r := range runes {
+		if unicode.IsSpace(r) {
+			spaces++
+		} else {
+			word = append(word, r)
+		}
+
+		if spaces > 0 {
+			if rw.StringWidth(string(lines[row]))+rw.StringWidth(string(word))+spaces > width {
+				row++
+				lines = append(lines, []rune{})
+			}
+			lines[row] = append(lines[row], word...)
+			lines[row] = append(lines[row], repeatSpaces(spaces)...)
+			spaces = 0
+			word = nil
+		} else {
+			// A double-width character at the end of the word may not fit
+			// on the line anymore.
+			lastCharLen := rw.RuneWidth(word[len(word)-1])
+			if rw.StringWidth(string(word))+lastCharLen > width {
+				// The word fills a whole line, so move on to the next line
+				// if this one has content already.
+				if len(lines[row]) > 0 {
+					row++
+					lines = append(lines, []rune{})
+				}
+				lines[row] = append(lines[row], word...)
+				word = nil
+			}
+		}
+	}
+
+	if rw.StringWidth(string(lines[row]))+rw.StringWidth(string(word))+spaces >= width {
+		lines = append(lines, []rune{})
+		lines[row+1] = append(lines[row+1], word...)
+		spaces++
+		lines[row+1] = append(lines[row+1], repeatSpaces(spaces)...)
+	} else {
+		lines[row] = append(lines[row], word...)
+		spaces++
+		lines[row] = append(lines[row], repeatSpaces(spaces)...)
+	}
+
+	return lines
+}
+
+func repeatSpaces(n int) []rune {
+	return []rune(strings.Repeat(" ", n))
+}
+
+func clamp(v, low, high int) int {
+	if high < low {
+		low, high = high, low
+	}
+	return min(high, max(low, v))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
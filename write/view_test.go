@@ -0,0 +1,37 @@
+package write
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNoSoftWrapLongLine(t *testing.T) {
+	long := strings.Repeat("x", 2*maxWidth)
+	m := newModel(long + "\nshort")
+	m.softWrap = false
+	m.setWidth()
+	text, _ := m.text()
+
+	for _, p := range []int{0, maxWidth - 1, maxWidth + 20, len(long)} {
+		m.setCursor(text, p)
+		m.scroll()
+		if lines := strings.Count(m.View(), "\n") + 1; lines != m.textarea.Height() {
+			t.Errorf("position %d: %d lines displayed, want %d", p, lines, m.textarea.Height())
+		}
+
+		down := press(m, tea.KeyMsg{Type: tea.KeyDown})
+		if got, want := down.textarea.Line(), 1; got != want {
+			t.Errorf("position %d: line after down = %d, want %d", p, got, want)
+		}
+		_ = down.View()
+	}
+}
+
+func TestPlainTextIsTheTextAreaView(t *testing.T) {
+	m := newModel("some text\nto edit")
+	if got, want := m.editorView(), m.textarea.View(); got != want {
+		t.Errorf("view = %q, want the text area view %q", got, want)
+	}
+}
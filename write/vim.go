@@ -0,0 +1,489 @@
+package write
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxUndo is the number of changes that can be undone in the vim mode.
+const maxUndo = 100
+
+// vim holds the state of the vim mode. The text is edited in the text area in
+// insert mode, the normal mode moves around and changes the text with the
+// commands of vim, such as dd, yy, p or ciw.
+type vim struct {
+	insert bool
+	// pending holds the keys of a command that is still being typed, such
+	// as the d of dd.
+	pending string
+	// register holds the text that was last yanked or deleted, which is
+	// pasted as whole lines if it was yanked or deleted as such.
+	register string
+	linewise bool
+	undo     []snapshot
+}
+
+// snapshot is the text and the position of the cursor before a change.
+type snapshot struct {
+	text []rune
+	pos  int
+}
+
+// mode returns the mode the text is edited in, along with the keys of the
+// command that is being typed.
+func (v vim) mode() string {
+	if v.insert {
+		return "-- INSERT --"
+	}
+	return strings.TrimSpace("-- NORMAL -- " + v.pending)
+}
+
+// updateVim handles the keys in vim mode. It returns false for the keys that
+// are left to the text area, such as the keys that are typed in insert mode.
+func (m *model) updateVim(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.vim.insert {
+		if msg.Type != tea.KeyEsc {
+			return nil, false
+		}
+		// Like in vim, the cursor moves back onto the last character typed.
+		m.vim.insert = false
+		text, p := m.text()
+		if p > lineStart(text, p) {
+			p--
+		}
+		m.setCursor(text, p)
+		return nil, true
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.vim.pending = ""
+		return nil, true
+	case tea.KeyEnter:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}}
+	case tea.KeyBackspace:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}}
+	case tea.KeySpace:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}}
+	case tea.KeyRunes:
+		// The keys bound to alt, such as alt+n, are left to gum write.
+		if msg.Alt {
+			return nil, false
+		}
+	default:
+		// The arrow keys and the keys bound to ctrl are left to the text
+		// area and the rest of gum write.
+		return nil, false
+	}
+
+	m.vim.pending += string(msg.Runes)
+	cmd, done := m.normal(m.vim.pending)
+	if done {
+		m.vim.pending = ""
+	}
+	return cmd, true
+}
+
+// normal runs the command of the keys typed in normal mode. It returns false
+// if the command is not complete yet. Unknown commands are complete, so that
+// they are discarded.
+//
+//nolint:gocyclo
+func (m *model) normal(keys string) (tea.Cmd, bool) {
+	// Commands may be preceded by the number of times to run them, such as
+	// 3dd, although 0 on its own moves to the start of the line.
+	n := 1
+	digits := strings.TrimLeftFunc(keys, unicode.IsDigit)
+	if count := keys[:len(keys)-len(digits)]; count != "" && count[0] != '0' {
+		n, _ = strconv.Atoi(count)
+		keys = digits
+	}
+	if keys == "" {
+		return nil, false
+	}
+
+	text, p := m.text()
+	start, end := lineStart(text, p), lineEnd(text, p)
+
+	switch keys {
+	case "i":
+		m.change(text, p)
+		m.enterInsert(text, p)
+	case "a":
+		m.change(text, p)
+		m.enterInsert(text, min(p+1, end))
+	case "I":
+		m.change(text, p)
+		m.enterInsert(text, firstNonBlank(text, p))
+	case "A":
+		m.change(text, p)
+		m.enterInsert(text, end)
+	case "o":
+		m.change(text, p)
+		m.enterInsert(insert(text, end, []rune("\n")), end+1)
+	case "O":
+		m.change(text, p)
+		m.enterInsert(insert(text, start, []rune("\n")), start)
+	case "x":
+		m.operate("d", text, p, p, min(p+n, end), false)
+	case "X":
+		m.operate("d", text, p, max(start, p-n), p, false)
+	case "D":
+		m.operate("d", text, p, p, end, false)
+	case "C":
+		m.operate("c", text, p, p, end, false)
+	case "s":
+		m.operate("c", text, p, p, min(p+n, end), false)
+	case "S":
+		m.operate("c", text, p, p, p, true)
+	case "Y":
+		m.operate("y", text, p, p, lineDown(text, p, n-1), true)
+	case "p", "P":
+		m.paste(text, p, n, keys == "P")
+	case "u":
+		for i := 0; i < n && len(m.vim.undo) > 0; i++ {
+			last := m.vim.undo[len(m.vim.undo)-1]
+			m.vim.undo = m.vim.undo[:len(m.vim.undo)-1]
+			m.setText(last.text, last.pos)
+		}
+	case "g", "r", "Z", "d", "c", "y":
+		return nil, false
+	case "ZZ":
+		m.quitting = true
+		return tea.Quit, true
+	default:
+		if strings.HasPrefix(keys, "r") {
+			// Replace the characters under the cursor, if there are as
+			// many left on the line.
+			r := []rune(keys[1:])
+			if len(r) == 1 && p+n <= end {
+				m.change(text, p)
+				for i := p; i < p+n; i++ {
+					text[i] = r[0]
+				}
+				m.setText(text, p+n-1)
+			}
+			return nil, true
+		}
+
+		if op := keys[:1]; strings.Contains("dcy", op) {
+			return nil, m.operator(op, keys[1:], text, p, n)
+		}
+
+		if t, _, linewise, ok := motion(text, p, n, keys); ok {
+			if linewise {
+				// Lines are moved between in the same column where possible.
+				t = min(lineStart(text, t)+(p-start), lastChar(text, t))
+			}
+			m.setCursor(text, min(t, lastChar(text, t)))
+		}
+	}
+	return nil, true
+}
+
+// operator applies the operator (d, c or y) to the text that the motion or
+// text object moves over. It returns false if the motion is not complete yet.
+func (m *model) operator(op, keys string, text []rune, p, n int) bool {
+	switch keys {
+	case "", "i", "a", "g":
+		return false
+	case op:
+		// dd, cc and yy apply to whole lines.
+		m.operate(op, text, p, p, lineDown(text, p, n-1), true)
+		return true
+	case "iw", "aw":
+		start, end := word(text, p, keys == "aw")
+		m.operate(op, text, p, start, end, false)
+		return true
+	}
+
+	// Like in vim, cw changes up to the end of the word.
+	if op == "c" && keys == "w" {
+		keys = "e"
+	}
+
+	t, inclusive, linewise, ok := motion(text, p, n, keys)
+	if !ok {
+		return true
+	}
+	if keys == "w" {
+		// dw and yw stop at the end of the line.
+		t = min(t, lineEnd(text, p))
+	}
+	start, end := min(p, t), max(p, t)
+	if inclusive && end < len(text) {
+		end++
+	}
+	m.operate(op, text, p, start, end, linewise)
+	return true
+}
+
+// operate deletes (d), changes (c) or yanks (y) the text from start up to
+// (but excluding) end, or the lines from start to end if linewise. The text
+// that is deleted or yanked is kept in the register.
+func (m *model) operate(op string, text []rune, p, start, end int, linewise bool) {
+	if linewise {
+		start, end = lineStart(text, start), lineEnd(text, end)
+		m.vim.register = string(text[start:end])
+		m.vim.linewise = true
+
+		// Deleted lines go with their newline, while the last line of the
+		// text takes the newline before it. Changed lines are left empty.
+		if op == "d" {
+			if end < len(text) {
+				end++
+			} else if start > 0 {
+				start--
+			}
+		}
+	} else if start < end {
+		m.vim.register = string(text[start:end])
+		m.vim.linewise = false
+	} else if op != "c" {
+		// There is nothing to delete or yank, but there is still something
+		// to change into.
+		return
+	}
+
+	switch op {
+	case "y":
+		m.setCursor(text, min(p, start))
+	case "d":
+		m.change(text, p)
+		text = remove(text, start, end)
+		if linewise {
+			m.setText(text, firstNonBlank(text, start))
+		} else {
+			m.setText(text, min(start, lastChar(text, start)))
+		}
+	case "c":
+		m.change(text, p)
+		m.enterInsert(remove(text, start, end), start)
+	}
+}
+
+// paste pastes the register n times after the cursor, or before it. Lines are
+// pasted below the current line, or above it.
+func (m *model) paste(text []rune, p, n int, before bool) {
+	if m.vim.register == "" {
+		return
+	}
+	m.change(text, p)
+
+	if m.vim.linewise {
+		lines := []rune(strings.Repeat(m.vim.register+"\n", n))
+		at, first := lineStart(text, p), lineStart(text, p)
+		if !before {
+			at = lineEnd(text, p) + 1
+			first = at
+			if at > len(text) {
+				// The last line has no newline to paste after.
+				lines = append([]rune("\n"), lines[:len(lines)-1]...)
+				at = len(text)
+			}
+		}
+		text = insert(text, at, lines)
+		m.setText(text, firstNonBlank(text, first))
+		return
+	}
+
+	at := p
+	if !before {
+		at = min(p+1, lineEnd(text, p))
+	}
+	paste := []rune(strings.Repeat(m.vim.register, n))
+	m.setText(insert(text, at, paste), at+len(paste)-1)
+}
+
+// enterInsert enters insert mode at the given position of the text. The
+// changes made in insert mode are undone at once, along with the change that
+// entered it.
+func (m *model) enterInsert(text []rune, p int) {
+	m.vim.insert = true
+	m.setText(text, p)
+}
+
+// change keeps the text before it is changed, so that the change can be
+// undone.
+func (m *model) change(text []rune, p int) {
+	m.vim.undo = append(m.vim.undo, snapshot{text: append([]rune(nil), text...), pos: p})
+	if len(m.vim.undo) > maxUndo {
+		m.vim.undo = m.vim.undo[1:]
+	}
+}
+
+// motion returns the position that the motion moves the cursor at p to when
+// repeated n times, whether the character there is part of the text that an
+// operator applies to, and whether it applies to whole lines.
+func motion(text []rune, p, n int, keys string) (t int, inclusive, linewise, ok bool) {
+	t = p
+	switch keys {
+	case "h":
+		t = max(lineStart(text, p), p-n)
+	case "l":
+		t = min(lineEnd(text, p), p+n)
+	case "j":
+		return lineDown(text, p, n), false, true, true
+	case "k":
+		for i := 0; i < n && lineStart(text, t) > 0; i++ {
+			t = lineStart(text, t) - 1
+		}
+		return t, false, true, true
+	case "0":
+		t = lineStart(text, p)
+	case "^":
+		t = firstNonBlank(text, p)
+	case "$":
+		t = lineEnd(text, lineDown(text, p, n-1))
+	case "w":
+		for i := 0; i < n; i++ {
+			t = wordForward(text, t)
+		}
+	case "b":
+		for i := 0; i < n; i++ {
+			t = wordBackward(text, t)
+		}
+	case "e":
+		for i := 0; i < n; i++ {
+			t = wordEnd(text, t)
+		}
+		return t, true, false, true
+	case "gg", "G":
+		// Both go to the n-th line if a count is given.
+		lines := strings.Count(string(text), "\n")
+		line := n - 1
+		if keys == "G" && n == 1 {
+			line = lines
+		}
+		t = lineDown(text, 0, min(line, lines))
+		return t, false, true, true
+	default:
+		return p, false, false, false
+	}
+	return t, false, false, true
+}
+
+// lastChar returns the position of the last character of the line at p, where
+// the cursor stays in normal mode.
+func lastChar(text []rune, p int) int {
+	return max(lineStart(text, p), lineEnd(text, p)-1)
+}
+
+// lineDown returns the position of the start of the line n lines below the
+// line at p, or of the last line.
+func lineDown(text []rune, p, n int) int {
+	p = lineStart(text, p)
+	for i := 0; i < n && lineEnd(text, p) < len(text); i++ {
+		p = lineEnd(text, p) + 1
+	}
+	return p
+}
+
+// firstNonBlank returns the position of the first character of the line at p
+// that is not a space.
+func firstNonBlank(text []rune, p int) int {
+	p = lineStart(text, p)
+	for p < len(text) && text[p] != '\n' && unicode.IsSpace(text[p]) {
+		p++
+	}
+	return p
+}
+
+// class returns the class of a character for the word motions: spaces,
+// letters and digits, or punctuation. A word is made of characters of the
+// same class.
+func class(r rune) int {
+	switch {
+	case unicode.IsSpace(r):
+		return 0
+	case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+		return 1
+	}
+	return 2 //nolint:gomnd
+}
+
+// wordForward returns the position of the start of the next word.
+func wordForward(text []rune, p int) int {
+	if p >= len(text) {
+		return p
+	}
+	if c := class(text[p]); c != 0 {
+		for p < len(text) && class(text[p]) == c {
+			p++
+		}
+	}
+	for p < len(text) && class(text[p]) == 0 {
+		p++
+	}
+	return p
+}
+
+// wordBackward returns the position of the start of the word before p, or of
+// the word p is in.
+func wordBackward(text []rune, p int) int {
+	for p > 0 && class(text[p-1]) == 0 {
+		p--
+	}
+	if p == 0 {
+		return 0
+	}
+	c := class(text[p-1])
+	for p > 0 && class(text[p-1]) == c {
+		p--
+	}
+	return p
+}
+
+// wordEnd returns the position of the last character of the word after p, or
+// of the word p is in.
+func wordEnd(text []rune, p int) int {
+	p++
+	for p < len(text) && class(text[p]) == 0 {
+		p++
+	}
+	if p >= len(text) {
+		return max(0, len(text)-1)
+	}
+	c := class(text[p])
+	for p+1 < len(text) && class(text[p+1]) == c {
+		p++
+	}
+	return p
+}
+
+// word returns the start and the end of the word at p, for the iw and aw text
+// objects. The word goes with the spaces that follow it (or precede it, at
+// the end of a line) when around is set.
+func word(text []rune, p int, around bool) (int, int) {
+	start, end := p, p
+	if p >= len(text) || text[p] == '\n' {
+		return start, end
+	}
+	same := func(i int) bool { return text[i] != '\n' && class(text[i]) == class(text[p]) }
+	for start > 0 && same(start-1) {
+		start--
+	}
+	for end < len(text) && same(end) {
+		end++
+	}
+	if !around {
+		return start, end
+	}
+
+	blank := func(i int) bool { return text[i] != '\n' && class(text[i]) == 0 }
+	if trailing := end; end < len(text) && blank(end) {
+		for end < len(text) && blank(end) {
+			end++
+		}
+		if trailing != end {
+			return start, end
+		}
+	}
+	for start > 0 && blank(start-1) {
+		start--
+	}
+	return start, end
+}
@@ -0,0 +1,65 @@
+package write
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestVimMovesOverWrappedLines(t *testing.T) {
+	const value = "日本語 テキスト 日本語\nline\n日本語 テキスト"
+	esc := tea.KeyMsg{Type: tea.KeyEsc}
+
+	for width := 6; width <= 24; width++ {
+		for p := 0; p <= len([]rune(value)); p++ {
+			m := newModel(value)
+			m.width = width
+			m.setWidth()
+			m.vim = &vim{}
+			text, _ := m.text()
+			m.setCursor(text, p)
+			row := m.textarea.Line()
+
+			m = press(m, runes("j"))
+			if want := min(row+1, 2); m.textarea.Line() != want {
+				t.Errorf("width %d, position %d: line after j = %d, want %d", width, p, m.textarea.Line(), want)
+			}
+			m = press(m, runes("k"), runes("$"), esc, runes("j"), runes("k"), runes("k"))
+			if m.textarea.Value() != value {
+				t.Fatalf("width %d, position %d: value changed to %q", width, p, m.textarea.Value())
+			}
+		}
+	}
+}
+
+func TestVimMovesDownFromWideLine(t *testing.T) {
+	value, fields := parseTemplate("日本語 テキスト\nline")
+	m := newModel(value)
+	m.fields = fields
+	m.vim = &vim{insert: true}
+	text, _ := m.text()
+	m.setCursor(text, 0)
+
+	m = press(m, runes("$"), tea.KeyMsg{Type: tea.KeyEsc}, runes("j"))
+	if got, want := m.textarea.Line(), 1; got != want {
+		t.Errorf("line = %d, want %d", got, want)
+	}
+}
+
+func TestVimLeavesAltKeys(t *testing.T) {
+	m := newModel("one two")
+	m.vim = &vim{}
+	lineNumbers := m.textarea.ShowLineNumbers
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}, Alt: true})
+	if m.textarea.ShowLineNumbers == lineNumbers {
+		t.Error("alt+n did not toggle the line numbers in normal mode")
+	}
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}, Alt: true})
+	if m.softWrap {
+		t.Error("alt+z did not toggle soft wrap in normal mode")
+	}
+	if m.vim.pending != "" {
+		t.Errorf("pending keys = %q, want none", m.vim.pending)
+	}
+}
@@ -4,32 +4,252 @@
 // It can be used to ask the user to write some long form of text (multi-line)
 // input. The text the user entered will be sent to stdout.
 // Text entry is completed with CTRL+D and aborted with CTRL+C or Escape.
+// CTRL+E opens the text in $EDITOR for heavier editing. ALT+N toggles the line
+// numbers and ALT+Z toggles soft wrap.
+//
+// With --recover-key the text is saved every few seconds, and offered to be
+// restored the next time the same key is given unless it was completed.
+//
+// With --format-cmd, CTRL+F pipes the text through the command to format it.
+//
+// With --template the initial value may hold fields such as ${1:scope}, which
+// TAB and SHIFT+TAB move between, in order, for their text to be typed over.
+//
+// With --vim the text is edited the way it is in vim, from insert mode. Escape
+// enters normal mode rather than completing the text entry, and ZZ completes
+// it from there.
 //
 // $ gum write > output.text
 package write
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type model struct {
 	aborted  bool
 	quitting bool
 	textarea textarea.Model
+	style    textarea.Style
+	width    int
+	offset   int
+	// Without soft wrap, lines are scrolled horizontally instead, from the
+	// column on.
+	softWrap    bool
+	column      int
+	highlighter *highlighter
+
+	showStatus  bool
+	statusStyle lipgloss.Style
+
+	// vim is set in vim mode.
+	vim *vim
+
+	// fields are the fields of the template, field is the one the cursor
+	// was last moved to, or -1.
+	fields     []field
+	field      int
+	fieldStyle lipgloss.Style
+
+	// The text is saved periodically to the recover path, unless it has not
+	// changed since. While restoring, the text of a previous session is
+	// displayed until it is kept or replaced by the original text.
+	recoverPath string
+	saved       string
+	restoring   bool
+	original    string
+
+	// The number of lines is limited to the line limit, and the number of
+	// characters to the char limit of the text area. The warning tells that
+	// the text was kept from going over them.
+	lineLimit    int
+	warning      string
+	warningStyle lipgloss.Style
+
+	// formatCmd formats the text, err holds why it could not.
+	formatCmd  string
+	err        string
+	errorStyle lipgloss.Style
 }
 
-func (m model) Init() tea.Cmd { return textarea.Blink }
+func (m model) Init() tea.Cmd {
+	if m.recoverPath != "" {
+		return tea.Batch(textarea.Blink, autosave())
+	}
+	return textarea.Blink
+}
 func (m model) View() string {
 	if m.quitting {
 		return ""
 	}
-	return m.textarea.View()
+	var footer []string
+	if m.restoring {
+		footer = append(footer, m.statusStyle.Render("Restored the text of a previous session, keep it? (y/n)"))
+	}
+	if m.vim != nil {
+		footer = append(footer, m.statusStyle.Render(m.vim.mode()))
+	}
+	if m.showStatus {
+		footer = append(footer, m.statusView())
+	}
+	if m.warning != "" {
+		footer = append(footer, m.warningStyle.Render(m.warning))
+	}
+	if m.err != "" {
+		footer = append(footer, m.errorStyle.Render(m.err))
+	}
+	if len(footer) > 0 {
+		return m.editorView() + "\n" + strings.Join(footer, "  ")
+	}
+	return m.editorView()
 }
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// The rows of the text area, the fields and the vim state are changed in
+	// place, so the previous model only holds on to the counts of the text
+	// area, and gets copies of the rest.
+	prev := m
+	prev.fields = append([]field(nil), m.fields...)
+	if m.vim != nil {
+		v := *m.vim
+		v.undo = append([]snapshot(nil), m.vim.undo...)
+		prev.vim = &v
+	}
+	lines, length := m.textarea.LineCount(), m.textarea.Length()
+	text, p := m.text()
+	before := string(text)
+	m, cmd := m.update(msg)
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.warning = ""
+		if m.err == prev.err {
+			m.err = ""
+		}
+	}
+
+	// Changes that go over the limits are undone, along with the state they
+	// changed. Text that was over the limits from the start can still be
+	// edited down.
+	if warning := m.overLimit(lines, length); warning != "" {
+		prev.setText(text, p)
+		prev.scroll()
+		prev.warning = warning
+		return prev, cmd
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && m.textarea.Value() == before && m.textarea.CharLimit > 0 &&
+		(msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) && m.textarea.Length() >= m.textarea.CharLimit {
+		// The text area itself keeps the text from going over the char
+		// limit, but silently.
+		m.warning = fmt.Sprintf("Limit of %d characters reached", m.textarea.CharLimit)
+	}
+
+	if len(m.fields) > 0 && !prev.restoring {
+		m.shiftFields([]rune(before))
+	}
+	return m, cmd
+}
+
+// overLimit returns a warning if the text went over a limit, with more lines
+// or characters than it had before.
+func (m model) overLimit(lines, length int) string {
+	if n := m.textarea.LineCount(); m.lineLimit > 0 && n > m.lineLimit && n > lines {
+		return fmt.Sprintf("Limit of %d lines reached", m.lineLimit)
+	}
+	if n := m.textarea.Length(); m.textarea.CharLimit > 0 && n > m.textarea.CharLimit && n > length {
+		return fmt.Sprintf("Limit of %d characters reached", m.textarea.CharLimit)
+	}
+	return ""
+}
+
+func (m model) update(msg tea.Msg) (model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case autosaveMsg:
+		// The text is saved on a best effort basis, an error is not worth
+		// interrupting the user for.
+		if value := m.textarea.Value(); value != m.saved && !m.restoring {
+			if err := saveRecovery(m.recoverPath, value); err == nil {
+				m.saved = value
+			}
+		}
+		return m, autosave()
+	case formatMsg:
+		if msg.err != nil {
+			m.err = msg.err.Error()
+			return m, nil
+		}
+		// The cursor stays where it was, as far as the text allows.
+		_, p := m.text()
+		m.setText([]rune(msg.text), p)
+		m.scroll()
+		return m, nil
+	case editorMsg:
+		// The text is left as it was if the editor failed.
+		if text, err := readEditor(msg); err == nil {
+			m.textarea.SetValue(text)
+			m.scroll()
+		}
+		return m, nil
 	case tea.KeyMsg:
+		if m.restoring && msg.String() != "ctrl+c" {
+			switch msg.String() {
+			case "y", "Y", "enter":
+				// The fields of the template are not in the restored text.
+				m.restoring = false
+				m.fields = nil
+			case "n", "N", "esc":
+				m.restoring = false
+				m.textarea.SetValue(m.original)
+				if len(m.fields) > 0 {
+					m.jump(1)
+				}
+				m.scroll()
+			}
+			return m, nil
+		}
+
+		if m.vim != nil {
+			if cmd, ok := m.updateVim(msg); ok {
+				m.scroll()
+				return m, cmd
+			}
+		}
+
+		// Without soft wrap, the cursor moves by lines rather than by the
+		// rows that the text area still wraps the widest lines on.
+		if !m.softWrap {
+			switch {
+			case key.Matches(msg, m.textarea.KeyMap.LineNext):
+				m.moveLine(1)
+				m.scroll()
+				return m, nil
+			case key.Matches(msg, m.textarea.KeyMap.LinePrevious):
+				m.moveLine(-1)
+				m.scroll()
+				return m, nil
+			}
+		}
+
 		switch msg.String() {
+		case "alt+n":
+			m.textarea.ShowLineNumbers = !m.textarea.ShowLineNumbers
+			m.setWidth()
+			m.scroll()
+			return m, nil
+		case "alt+z":
+			m.softWrap = !m.softWrap
+			m.setWidth()
+			m.scroll()
+			return m, nil
+		case "ctrl+e":
+			return m, openEditor(m.textarea.Value())
+		case "ctrl+f":
+			if m.formatCmd != "" {
+				return m, format(m.formatCmd, m.textarea.Value())
+			}
 		case "ctrl+c":
 			m.aborted = true
 			m.quitting = true
@@ -37,10 +257,51 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "esc", "ctrl+d":
 			m.quitting = true
 			return m, tea.Quit
+		case "tab", "shift+tab":
+			if len(m.fields) > 0 {
+				if msg.String() == "tab" {
+					m.jump(1)
+				} else {
+					m.jump(-1)
+				}
+				m.scroll()
+				return m, nil
+			}
+		}
+
+		// What is typed in a field of the template replaces its text.
+		if len(m.fields) > 0 {
+			switch msg.Type {
+			case tea.KeyRunes, tea.KeySpace:
+				m.typeOver()
+			case tea.KeyBackspace, tea.KeyDelete:
+				if m.typeOver() {
+					m.scroll()
+					return m, nil
+				}
+			}
 		}
 	}
 
 	var cmd tea.Cmd
 	m.textarea, cmd = m.textarea.Update(msg)
+	m.scroll()
 	return m, cmd
 }
+
+// setWidth sets the width of the text area, which depends on the line numbers.
+// Without soft wrap the text area is as wide as it can be, so that it only
+// wraps the lines that could not be displayed anyway.
+func (m *model) setWidth() {
+	if m.softWrap {
+		m.textarea.SetWidth(m.width)
+	} else {
+		m.textarea.SetWidth(m.width + maxWidth)
+	}
+}
+
+// scroll keeps the cursor in view.
+func (m *model) scroll() {
+	m.offset = m.scrollOffset()
+	m.column = m.scrollColumn()
+}
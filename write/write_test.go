@@ -0,0 +1,105 @@
+package write
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newModel returns a model for the value, the way gum write sets it up, with
+// the cursor at the end of the text.
+func newModel(value string) model {
+	a := textarea.New()
+	a.Focus()
+	a.SetValue(value)
+	m := model{textarea: a, width: 40, softWrap: true, field: -1}
+	m.setWidth()
+	return m
+}
+
+// press sends the keys to the model, one after the other.
+func press(m model, keys ...tea.KeyMsg) model {
+	for _, key := range keys {
+		tm, _ := m.Update(key)
+		m = tm.(model)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+var (
+	enter = tea.KeyMsg{Type: tea.KeyEnter}
+	tab   = tea.KeyMsg{Type: tea.KeyTab}
+)
+
+func TestLineLimitUndoesChange(t *testing.T) {
+	m := newModel("first\nsecond\nthird")
+	m.lineLimit = 3
+	text, _ := m.text()
+	m.setCursor(text, 0)
+
+	m = press(m, enter)
+	if got, want := m.textarea.Value(), "first\nsecond\nthird"; got != want {
+		t.Errorf("value = %q, want %q", got, want)
+	}
+	if m.warning == "" {
+		t.Error("no warning that the limit was reached")
+	}
+	if _, p := m.text(); p != 0 {
+		t.Errorf("cursor = %d, want 0", p)
+	}
+}
+
+func TestLineLimitKeepsFields(t *testing.T) {
+	value, fields := parseTemplate("${1:scope}: ${2:subject}\n\n${3:body}")
+	m := newModel(value)
+	m.lineLimit = 3
+	m.fields = fields
+	m.jump(1)
+
+	m = press(m, tab, enter, tab, runes("k"))
+	if got, want := m.textarea.Value(), "scope: subject\n\nk"; got != want {
+		t.Errorf("value = %q, want %q", got, want)
+	}
+}
+
+func TestCharLimitUndoesChange(t *testing.T) {
+	m := newModel("abc")
+	m.textarea.CharLimit = 3
+	text, _ := m.text()
+	m.setCursor(text, 1)
+
+	m = press(m, enter)
+	if got, want := m.textarea.Value(), "abc"; got != want {
+		t.Errorf("value = %q, want %q", got, want)
+	}
+	if m.warning == "" {
+		t.Error("no warning that the limit was reached")
+	}
+}
+
+func TestSetCursorOnWrappedLines(t *testing.T) {
+	value := "日本語の文章はとても長くて、この行は何度も折り返されてしまいます。\n" +
+		strings.Repeat("wrapped words ", 8) + "\nlast"
+	m := newModel(value)
+	text := []rune(value)
+
+	// The cursor goes down from the start as well as up from the end.
+	for p := 0; p <= len(text); p++ {
+		m.setCursor(text, p)
+		if _, got := m.text(); got != p {
+			t.Fatalf("cursor = %d, want %d", got, p)
+		}
+	}
+	for p := len(text); p >= 0; p-- {
+		m.setCursor(text, p)
+		if _, got := m.text(); got != p {
+			t.Fatalf("cursor = %d, want %d", got, p)
+		}
+	}
+}